@@ -10,16 +10,56 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "github.com/NikitaBelov-mobile/car-social/docs"
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
 	"github.com/NikitaBelov-mobile/car-social/internal/config"
 	"github.com/NikitaBelov-mobile/car-social/internal/database"
 	authDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/auth"
+	carDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/car"
+	commentDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/comment"
+	feedDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/feed"
+	followDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/follow"
+	mediaDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/database/migrate"
+	postDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/post"
+	reactionDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/reaction"
+	searchDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/search"
 	userDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/user"
+	verificationDatabase "github.com/NikitaBelov-mobile/car-social/internal/database/verification"
+	appLogger "github.com/NikitaBelov-mobile/car-social/internal/logger"
+	"github.com/NikitaBelov-mobile/car-social/internal/metrics"
+	mediaService "github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/password"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/phone"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/ratelimit"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/session"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/sms"
 	"github.com/NikitaBelov-mobile/car-social/internal/service/token"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/verification"
 	authHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/auth"
+	carHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/car"
+	commentHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/comment"
+	followHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/follow"
+	healthHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/health"
+	mediaHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	postHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/post"
+	reactionHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+	searchHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/search"
 	userHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/user"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/migrations"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -32,32 +72,296 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	logger := appLogger.New(os.Stdout, cfg.Log)
+	slog.SetDefault(logger)
+
 	// Подключение к базе данных
 	db, err := database.NewPostgresDB(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		logger.Error("failed to initialize database", "error", err)
+		os.Exit(1)
 	}
 
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	logger.Info("connected to database")
+
+	migrator, err := migrate.New(db, migrations.FS, logger)
+	if err != nil {
+		logger.Error("failed to load migrations", "error", err)
+		os.Exit(1)
 	}
 
-	log.Println("Successfully connected to database")
+	if cfg.DB.AutoMigrate {
+		if err := migrator.Up(context.Background()); err != nil {
+			logger.Error("failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	jwtService, err := token.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	if err != nil {
+		logger.Error("failed to initialize token manager", "error", err)
+		os.Exit(1)
+	}
 
-	jwtService, err := token.NewTokenManager("asdasd")
+	phones, err := phone.NewNormalizer(cfg.Phone.DefaultRegion)
+	if err != nil {
+		logger.Error("failed to initialize phone normalizer", "error", err)
+		os.Exit(1)
+	}
 
-	userDB := userDatabase.NewUserRepositoryImpl(db)
-	authDB := authDatabase.NewAuthRepositoryImpl(db)
+	passwords, err := password.NewHasher(cfg.Password)
+	if err != nil {
+		logger.Error("failed to initialize password hasher", "error", err)
+		os.Exit(1)
+	}
 
-	userRoute := userHandler.NewHandler(userDB)
-	authRoute := authHandler.NewHandler(userDB, authDB, jwtService)
+	registry := metrics.NewRegistry()
+	if err := metrics.RegisterDBStats(registry, db, cfg.DB.DBName); err != nil {
+		logger.Error("failed to register database metrics", "error", err)
+		os.Exit(1)
+	}
+	httpMetrics := metrics.NewHTTP(registry)
+	authMetrics := metrics.NewAuth(registry)
 
-	router := gin.Default()
+	userDB := userDatabase.NewUserRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	profileDB := userDatabase.NewProfileRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	authDB := authDatabase.NewAuthRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	carDB := carDatabase.NewCarRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	postDB := postDatabase.NewPostRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	followDB := followDatabase.NewFollowRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	feedDB := feedDatabase.NewFeedRepositoryImpl(postDB)
+	commentDB := commentDatabase.NewCommentRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	reactionDB := reactionDatabase.NewReactionRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	searchDB := searchDatabase.NewSearchRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	verificationDB := verificationDatabase.NewVerificationRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
+	mediaDB := mediaDatabase.NewMediaRepositoryImpl(db, cfg.DB.QueryTimeout, logger)
 
-	userRoute.Register(&router.RouterGroup)
-	authRoute.Register(&router.RouterGroup)
+	// Хранилище загруженных изображений
+	var mediaStore mediaService.MediaStore
+	switch cfg.Media.Backend {
+	case config.MediaBackendS3:
+		mediaStore, err = mediaService.NewS3Store(context.Background(), cfg.Media.S3)
+	default:
+		mediaStore, err = mediaService.NewLocalStore(cfg.Media.Local.Dir)
+	}
+	if err != nil {
+		logger.Error("failed to initialize media store", "error", err)
+		os.Exit(1)
+	}
+	mediaStorage := mediaService.NewService(mediaStore, mediaDB, cfg.Media, logger)
+
+	// Другие SMS-шлюзы подключаются реализацией sms.SMSSender
+	var smsSender sms.SMSSender = sms.NewLogSender(logger)
+	if cfg.SMS.Backend == config.SMSBackendTwilio {
+		smsSender = sms.NewTwilioSender(cfg.SMS.Twilio, cfg.SMS.Timeout)
+	}
+	verifier := verification.NewService(verificationDB, smsSender, cfg.OTP)
+
+	// Фоновые задачи работают до остановки сервера
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	// Фоновая очистка истекших сессий
+	sessionSweeper := session.NewSweeper(authDB, cfg.Auth.SessionCleanupInterval, logger)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		sessionSweeper.Run(workersCtx)
+	}()
+
+	// Фоновая очистка истекших одноразовых кодов
+	codeSweeper := verification.NewSweeper(verificationDB, cfg.OTP.CleanupInterval, verification.Retention(cfg.OTP), logger)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		codeSweeper.Run(workersCtx)
+	}()
+
+	// Ограничение частоты запросов; хранилище в Postgres делит лимиты
+	// между репликами
+	var rateStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Backend == config.RateLimitBackendPostgres {
+		rateStore = ratelimit.NewPostgresStore(db, cfg.DB.QueryTimeout, logger)
+	}
+	limiter := ratelimit.NewLimiter(rateStore)
+	lockout := ratelimit.LockoutPolicy{
+		Threshold: cfg.RateLimit.LockoutThreshold,
+		Base:      cfg.RateLimit.LockoutBase,
+		Max:       cfg.RateLimit.LockoutMax,
+		Window:    cfg.RateLimit.FailureWindow,
+	}
+	signInGuard := ratelimit.NewSignInGuard(rateStore, ratelimit.Limit(cfg.RateLimit.SignInPhone), lockout)
+	resetGuard := ratelimit.NewPhoneLimiter(rateStore, "reset_password_phone", ratelimit.Limit(cfg.RateLimit.ResetPasswordPhone))
+
+	rateCleaner := ratelimit.NewCleaner(rateStore, cfg.RateLimit.CleanupInterval, ratelimit.StaleAfter(lockout,
+		ratelimit.Limit(cfg.RateLimit.Auth),
+		ratelimit.Limit(cfg.RateLimit.API),
+		ratelimit.Limit(cfg.RateLimit.SignUp),
+		ratelimit.Limit(cfg.RateLimit.SignInIP),
+		ratelimit.Limit(cfg.RateLimit.SignInPhone),
+		ratelimit.Limit(cfg.RateLimit.ResetPasswordPhone),
+	), logger)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		rateCleaner.Run(workersCtx)
+	}()
+
+	healthRoute := healthHandler.NewHandler(db, migrator, cfg.Server.ReadinessTimeout, logger)
+	userRoute := userHandler.NewHandler(userDB, profileDB, mediaDB, mediaStorage, phones, passwords, logger)
+	authRoute := authHandler.NewHandler(userDB, authDB, jwtService, verifier, phones, passwords, signInGuard, resetGuard, logger, authMetrics, cfg.Auth.RequirePhoneVerification)
+	carRoute := carHandler.NewHandler(userDB, carDB, mediaDB, mediaStorage, reactionDB)
+	postRoute := postHandler.NewHandler(userDB, carDB, postDB, feedDB, mediaDB, mediaStorage, reactionDB)
+	commentRoute := commentHandler.NewHandler(postDB, commentDB, reactionDB)
+	reactionRoute := reactionHandler.NewHandler(reactionDB)
+	searchRoute := searchHandler.NewHandler(searchDB, mediaDB, mediaStorage)
+	followRoute := followHandler.NewHandler(userDB, followDB)
+	mediaRoute := mediaHandler.NewHandler(mediaDB, mediaStorage)
+
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+	// Без явного списка gin доверяет X-Forwarded-For от любого клиента, и
+	// лимиты по IP обходятся подменой заголовка
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Error("invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+	router.Use(
+		middleware.RequestID(),
+		middleware.AccessLog(logger),
+		middleware.Metrics(httpMetrics),
+		middleware.Errors(),
+		middleware.Recovery(logger),
+	)
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apperror.NotFound("route_not_found", "route not found"))
+	})
+
+	healthRoute.Register(&router.RouterGroup)
+
+	// Публичные маршруты ограничиваются по IP, защищенные - по пользователю
+	public := router.Group("/", middleware.RateLimit(limiter, "auth", ratelimit.Limit(cfg.RateLimit.Auth), middleware.ByIP))
+
+	// Маршруты, требующие access token
+	protected := router.Group("/",
+		middleware.Auth(jwtService),
+		middleware.RateLimit(limiter, "api", ratelimit.Limit(cfg.RateLimit.API), middleware.ByUser),
+	)
+
+	userRoute.Register(protected)
+	carRoute.Register(protected)
+	postRoute.Register(protected)
+	commentRoute.Register(protected)
+	reactionRoute.Register(protected)
+	searchRoute.Register(protected)
+	followRoute.Register(protected)
+	mediaRoute.Register(protected)
+	authRoute.Register(public, protected, authHandler.RateLimits{
+		SignUp: middleware.RateLimit(limiter, "sign_up", ratelimit.Limit(cfg.RateLimit.SignUp), middleware.ByIP),
+		SignIn: middleware.RateLimit(limiter, "sign_in", ratelimit.Limit(cfg.RateLimit.SignInIP), middleware.ByIP),
+	})
+
+	// Файлы локального хранилища раздает сам сервер. nosniff не дает
+	// браузеру интерпретировать изображение как другой тип содержимого.
+	if cfg.Media.Backend == config.MediaBackendLocal {
+		uploads := router.Group("/uploads", func(c *gin.Context) {
+			c.Header("X-Content-Type-Options", "nosniff")
+		})
+		uploads.Static("/", cfg.Media.Local.Dir)
+	}
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.Run(":8080")
+	srv := &http.Server{
+		Addr:              cfg.Server.Address,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+
+	// Метрики отдаются отдельным сервером на внутреннем адресе, чтобы они
+	// не были доступны снаружи вместе с API
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler(registry))
+	metricsSrv := &http.Server{
+		Addr:              cfg.Server.MetricsAddress,
+		Handler:           metricsMux,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("starting server", "address", cfg.Server.Address)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	go func() {
+		logger.Info("starting metrics server", "address", cfg.Server.MetricsAddress)
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- fmt.Errorf("metrics server: %w", err)
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down server")
+	case err := <-serverErr:
+		logger.Error("server failed", "error", err)
+		exitCode = 1
+	}
+
+	// Повторный сигнал завершит процесс сразу
+	stop()
+
+	// Сначала снимаем готовность и даем балансировщику время убрать реплику,
+	// продолжая обслуживать запросы
+	healthRoute.SetShuttingDown()
+	if exitCode == 0 {
+		time.Sleep(cfg.Server.DrainDelay)
+	}
+
+	// Перестаем принимать соединения и ждем завершения текущих запросов;
+	// по истечении ShutdownTimeout оставшиеся соединения закрываются
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to drain connections", "error", err)
+		srv.Close()
+		exitCode = 1
+	}
+	cancel()
+
+	// Метрики отдаются до конца завершения API, чтобы последний сбор
+	// увидел итоговые значения
+	metricsCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	if err := metricsSrv.Shutdown(metricsCtx); err != nil {
+		logger.Error("failed to stop metrics server", "error", err)
+		metricsSrv.Close()
+		exitCode = 1
+	}
+	cancel()
+
+	stopWorkers()
+	workers.Wait()
+
+	if err := db.Close(); err != nil {
+		logger.Error("failed to close database", "error", err)
+		exitCode = 1
+	}
+
+	logger.Info("server stopped")
+	os.Exit(exitCode)
 }
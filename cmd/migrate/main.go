@@ -0,0 +1,113 @@
+// Команда migrate управляет схемой базы данных с помощью встроенных миграций.
+//
+// Использование:
+//
+//	migrate up          применить все новые миграции
+//	migrate down [N]    откатить N последних миграций (по умолчанию одну)
+//	migrate status      показать текущую версию и список миграций
+//	migrate goto N      привести схему к версии N (0 - откатить все)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/NikitaBelov-mobile/car-social/internal/database/migrate"
+	"github.com/NikitaBelov-mobile/car-social/internal/logger"
+	"github.com/NikitaBelov-mobile/car-social/migrations"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate up | down [N] | status | goto N")
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS, logger.New(os.Stderr, cfg.Log))
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, migrator, flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		return migrator.Up(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps: %q", args[0])
+			}
+			steps = n
+		}
+		return migrator.Down(ctx, steps)
+
+	case "goto":
+		if len(args) == 0 {
+			return fmt.Errorf("goto requires a version")
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version: %q", args[0])
+		}
+		return migrator.Goto(ctx, uint(version))
+
+	case "status":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d", version)
+		if dirty {
+			fmt.Print(" (dirty)")
+		}
+		fmt.Println()
+
+		for _, migration := range migrator.Migrations() {
+			mark := " "
+			if migration.Version <= version {
+				mark = "x"
+			}
+			fmt.Printf("[%s] %06d_%s\n", mark, migration.Version, migration.Name)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
@@ -0,0 +1,142 @@
+// Команда normalize-phones приводит номера в users.phone к формату E.164.
+//
+// Номера, которые не удается разобрать, и номера, которые после
+// нормализации совпадают у нескольких пользователей, не изменяются и
+// выводятся в отчете для ручного разбора. С флагом -dry-run изменения
+// не записываются.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/phone"
+)
+
+type userPhone struct {
+	ID    int
+	Phone string
+}
+
+type phoneUpdate struct {
+	ID   int
+	From string
+	To   string
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "только вывести отчет, не изменяя данные")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	phones, err := phone.NewNormalizer(cfg.Phone.DefaultRegion)
+	if err != nil {
+		log.Fatalf("Failed to initialize phone normalizer: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	users, err := loadUserPhones(db)
+	if err != nil {
+		log.Fatalf("Failed to load users: %v", err)
+	}
+
+	// Группируем пользователей по нормализованному номеру
+	normalized := make(map[int]string, len(users))
+	groups := make(map[string][]userPhone)
+	for _, u := range users {
+		e164, err := phones.Normalize(u.Phone)
+		if err != nil {
+			fmt.Printf("INVALID  user=%d phone=%q\n", u.ID, u.Phone)
+			continue
+		}
+		normalized[u.ID] = e164
+		groups[e164] = append(groups[e164], u)
+	}
+
+	updates := make([]phoneUpdate, 0)
+	collisions := 0
+	for e164, group := range groups {
+		if len(group) > 1 {
+			collisions++
+			fmt.Printf("COLLISION %s:", e164)
+			for _, u := range group {
+				fmt.Printf(" user=%d phone=%q", u.ID, u.Phone)
+			}
+			fmt.Println()
+			continue
+		}
+
+		if u := group[0]; u.Phone != e164 {
+			updates = append(updates, phoneUpdate{ID: u.ID, From: u.Phone, To: e164})
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool { return updates[i].ID < updates[j].ID })
+	for _, u := range updates {
+		fmt.Printf("UPDATE   user=%d %q -> %q\n", u.ID, u.From, u.To)
+	}
+
+	fmt.Printf("Total: %d users, %d to update, %d invalid, %d collisions\n",
+		len(users), len(updates), len(users)-len(normalized), collisions)
+
+	if *dryRun || len(updates) == 0 {
+		return
+	}
+
+	if err := applyUpdates(db, updates); err != nil {
+		log.Fatalf("Failed to update phones: %v", err)
+	}
+
+	log.Printf("Updated %d phone numbers", len(updates))
+}
+
+func loadUserPhones(db *sql.DB) ([]userPhone, error) {
+	rows, err := db.Query(`SELECT id, phone FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]userPhone, 0)
+	for rows.Next() {
+		var u userPhone
+		if err := rows.Scan(&u.ID, &u.Phone); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// applyUpdates записывает номера в одной транзакции. Коллизии к этому
+// моменту уже исключены, поэтому уникальность users.phone не нарушается.
+func applyUpdates(db *sql.DB, updates []phoneUpdate) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, u := range updates {
+		if _, err := tx.Exec(`UPDATE users SET phone = $1, updated_at = NOW() WHERE id = $2`, u.To, u.ID); err != nil {
+			return fmt.Errorf("user %d: %w", u.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,105 @@
+// Package apperror описывает ошибки предметной области.
+//
+// Репозитории и сервисы возвращают ошибки, созданные конструкторами этого
+// пакета, а HTTP-слой выбирает статус ответа по виду ошибки:
+//
+//	if errors.Is(err, apperror.ErrNotFound) { ... }
+package apperror
+
+import (
+	"errors"
+	"time"
+)
+
+// Виды ошибок. Каждая *Error относится к одному из них.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrTooLarge        = errors.New("too large")
+	ErrUnsupportedType = errors.New("unsupported type")
+)
+
+// Error - ошибка с машиночитаемым кодом и сообщением, которое можно
+// показать клиенту
+type Error struct {
+	// Kind - вид ошибки, одна из переменных Err* этого пакета
+	Kind    error
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сопоставляет ошибку с ее видом, чтобы работал errors.Is(err, ErrNotFound)
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func New(kind error, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func InvalidInput(code, message string) *Error {
+	return New(ErrInvalidInput, code, message)
+}
+
+func TooManyRequests(code, message string) *Error {
+	return New(ErrTooManyRequests, code, message)
+}
+
+func TooLarge(code, message string) *Error {
+	return New(ErrTooLarge, code, message)
+}
+
+func UnsupportedType(code, message string) *Error {
+	return New(ErrUnsupportedType, code, message)
+}
+
+// WithRetryAfter дополняет ошибку временем, через которое запрос можно
+// повторить. HTTP-слой передает его клиенту в заголовке Retry-After.
+func WithRetryAfter(err *Error, after time.Duration) error {
+	return &retryAfterError{err: err, after: after}
+}
+
+type retryAfterError struct {
+	err   *Error
+	after time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.after
+}
@@ -1,42 +1,771 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+
+	MediaBackendLocal = "local"
+	MediaBackendS3    = "s3"
+
+	SMSBackendLog    = "log"
+	SMSBackendTwilio = "twilio"
+)
+
+// minJWTSecretLength - минимальная длина ключа подписи JWT в production
+const minJWTSecretLength = 32
+
+// Значения, которые нельзя использовать как секреты в production
+var weakSecrets = map[string]bool{
+	"secret":    true,
+	"changeme":  true,
+	"change-me": true,
+	"password":  true,
+	"postgres":  true,
+	"asdasd":    true,
+}
+
 type Config struct {
-	DB DatabaseConfig
+	// Env - окружение: development или production
+	Env    string         `yaml:"env"`
+	Log    LogConfig      `yaml:"log"`
+	Server ServerConfig   `yaml:"server"`
+	DB     DatabaseConfig `yaml:"db"`
+	Auth   AuthConfig     `yaml:"auth"`
+	OTP    OTPConfig      `yaml:"otp"`
+	Phone  PhoneConfig    `yaml:"phone"`
+	SMS    SMSConfig      `yaml:"sms"`
+
+	Password PasswordConfig `yaml:"password"`
+	Media    MediaConfig    `yaml:"media"`
+
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// LogConfig задает параметры логирования
+type LogConfig struct {
+	// Level - уровень логирования: debug, info, warn или error
+	Level string `yaml:"level"`
+	// Format - формат записей: json или text
+	Format string `yaml:"format"`
+}
+
+// ServerConfig задает параметры HTTP-сервера
+type ServerConfig struct {
+	Address string `yaml:"address"`
+	// MetricsAddress - адрес внутреннего сервера с /metrics. Метрики не
+	// отдаются на публичном адресе, и порт не должен быть доступен извне.
+	MetricsAddress string        `yaml:"metrics_address"`
+	ReadTimeout    time.Duration `yaml:"read_timeout"`
+	WriteTimeout   time.Duration `yaml:"write_timeout"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes"`
+	// ShutdownTimeout ограничивает время завершения обрабатываемых запросов
+	// при остановке сервера
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay - пауза между переводом /readyz в неготовое состояние и
+	// остановкой приема соединений, чтобы балансировщик успел убрать реплику
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// ReadinessTimeout ограничивает время проверок базы в /readyz
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+	// TrustedProxies - адреса и подсети обратных прокси, которым можно
+	// доверять X-Forwarded-For при определении IP клиента. По умолчанию
+	// пусто: IP берется из соединения, и клиент не может подменить его
+	// заголовком, чтобы обойти лимиты по IP.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+	// QueryTimeout ограничивает время выполнения одного запроса к базе
+	QueryTimeout    time.Duration `yaml:"query_timeout"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// AutoMigrate применяет встроенные миграции при запуске сервера
+	AutoMigrate bool `yaml:"auto_migrate"`
 }
 
-func LoadConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		return nil, err
+type AuthConfig struct {
+	// JWTSecret - ключ подписи access token
+	JWTSecret              string        `yaml:"jwt_secret"`
+	AccessTokenTTL         time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL        time.Duration `yaml:"refresh_token_ttl"`
+	SessionCleanupInterval time.Duration `yaml:"session_cleanup_interval"`
+	// RequirePhoneVerification запрещает вход до подтверждения номера телефона
+	RequirePhoneVerification bool `yaml:"require_phone_verification"`
+}
+
+// OTPConfig задает параметры одноразовых SMS-кодов
+type OTPConfig struct {
+	CodeTTL         time.Duration `yaml:"code_ttl"`
+	ResendInterval  time.Duration `yaml:"resend_interval"`
+	MaxCodesPerHour int           `yaml:"max_codes_per_hour"`
+	MaxAttempts     int           `yaml:"max_attempts"`
+	// CleanupInterval - период удаления истекших кодов
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+// SMSConfig задает шлюз для отправки SMS
+type SMSConfig struct {
+	// Backend - log (сообщения не отправляются, в лог пишется только факт
+	// отправки; для разработки) или twilio. В production log запрещен.
+	Backend string `yaml:"backend"`
+	// Timeout ограничивает время запроса к шлюзу
+	Timeout time.Duration `yaml:"timeout"`
+	Twilio  TwilioConfig  `yaml:"twilio"`
+}
+
+// TwilioConfig задает подключение к Twilio Messaging API
+type TwilioConfig struct {
+	AccountSID string `yaml:"account_sid"`
+	AuthToken  string `yaml:"auth_token"`
+	// From - номер отправителя в формате E.164
+	From string `yaml:"from"`
+}
+
+// PasswordConfig задает алгоритм и параметры хеширования паролей. Хеши,
+// созданные с другим алгоритмом или параметрами, проверяются как раньше и
+// пересчитываются при следующем входе пользователя.
+type PasswordConfig struct {
+	// Algorithm - алгоритм для новых хешей: argon2id или bcrypt
+	Algorithm  string       `yaml:"algorithm"`
+	BcryptCost int          `yaml:"bcrypt_cost"`
+	Argon2     Argon2Config `yaml:"argon2"`
+}
+
+// Argon2Config задает параметры Argon2id
+type Argon2Config struct {
+	// MemoryKiB - объем памяти в KiB
+	MemoryKiB   uint32 `yaml:"memory_kib"`
+	Iterations  uint32 `yaml:"iterations"`
+	Parallelism uint8  `yaml:"parallelism"`
+}
+
+// MediaConfig задает хранилище и обработку загружаемых изображений
+type MediaConfig struct {
+	// Backend - хранилище файлов: local (каталог на диске) или s3
+	// (S3-совместимое объектное хранилище)
+	Backend string `yaml:"backend"`
+	// PublicURL - адрес, по которому клиенты получают файлы; к нему
+	// добавляется ключ файла. Для local файлы раздает сам сервер по /uploads.
+	PublicURL string `yaml:"public_url"`
+	// MaxUploadSize - максимальный размер загружаемого файла в байтах
+	MaxUploadSize int `yaml:"max_upload_size"`
+	// MaxPixels - максимальное число пикселей изображения; защищает от
+	// файлов, которые при декодировании занимают гигабайты памяти
+	MaxPixels int `yaml:"max_pixels"`
+	// ThumbnailSizes - размеры миниатюр по длинной стороне в пикселях
+	ThumbnailSizes []int `yaml:"thumbnail_sizes"`
+	JPEGQuality    int   `yaml:"jpeg_quality"`
+
+	Local LocalMediaConfig `yaml:"local"`
+	S3    S3Config         `yaml:"s3"`
+}
+
+type LocalMediaConfig struct {
+	Dir string `yaml:"dir"`
+}
+
+// S3Config задает подключение к S3-совместимому хранилищу (AWS S3, MinIO)
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	UseSSL    bool   `yaml:"use_ssl"`
+}
+
+// PhoneConfig задает правила нормализации номеров телефонов
+type PhoneConfig struct {
+	// DefaultRegion - регион ISO 3166-1 для номеров без кода страны
+	DefaultRegion string `yaml:"default_region"`
+}
+
+// RateLimitConfig задает ограничения частоты запросов по группам маршрутов
+// и блокировку входа после неудачных попыток
+type RateLimitConfig struct {
+	// Backend - хранилище лимитов: memory (одна реплика) или postgres
+	// (общее для всех реплик)
+	Backend string `yaml:"backend"`
+	// Auth - лимит на все маршруты /auth с одного IP
+	Auth Rate `yaml:"auth"`
+	// API - лимит на маршруты, требующие access token, для одного пользователя
+	API         Rate `yaml:"api"`
+	SignUp      Rate `yaml:"sign_up"`
+	SignInIP    Rate `yaml:"sign_in_ip"`
+	SignInPhone Rate `yaml:"sign_in_phone"`
+	// ResetPasswordPhone - лимит попыток сброса пароля для одного номера
+	ResetPasswordPhone Rate `yaml:"reset_password_phone"`
+	// LockoutThreshold - число неудачных попыток входа, после которого номер
+	// или IP блокируется на LockoutBase; каждая следующая неудача удваивает
+	// блокировку, но не больше LockoutMax
+	LockoutThreshold int           `yaml:"lockout_threshold"`
+	LockoutBase      time.Duration `yaml:"lockout_base"`
+	LockoutMax       time.Duration `yaml:"lockout_max"`
+	// FailureWindow - через сколько после последней неудачи счетчик
+	// неудачных попыток сбрасывается
+	FailureWindow   time.Duration `yaml:"failure_window"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval"`
+}
+
+// Rate - не больше Requests запросов за Period. Записывается как
+// "<запросы>/<период>", например "10/1m".
+type Rate struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Requests, r.Period)
+}
+
+func (r Rate) valid() bool {
+	return r.Requests > 0 && r.Period > 0
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(text []byte) error {
+	requests, period, ok := strings.Cut(string(text), "/")
+	if !ok {
+		return fmt.Errorf("invalid rate %q: expected <requests>/<period>", text)
 	}
 
+	n, err := strconv.Atoi(requests)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q: %w", text, err)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q: %w", text, err)
+	}
+
+	r.Requests = n
+	r.Period = d
+	return nil
+}
+
+func defaultConfig() *Config {
 	return &Config{
+		Env: EnvDevelopment,
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogFormatJSON,
+		},
+		Server: ServerConfig{
+			Address:          ":8080",
+			MetricsAddress:   ":9090",
+			ReadTimeout:      10 * time.Second,
+			WriteTimeout:     30 * time.Second,
+			IdleTimeout:      2 * time.Minute,
+			MaxHeaderBytes:   1 << 20,
+			ShutdownTimeout:  15 * time.Second,
+			DrainDelay:       5 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+		},
 		DB: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			Username: getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "car_social"),
+			Host:            "localhost",
+			Port:            "5432",
+			Username:        "postgres",
+			DBName:          "car_social",
+			SSLMode:         "disable",
+			QueryTimeout:    5 * time.Second,
+			MaxOpenConns:    100,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:         15 * time.Minute,
+			RefreshTokenTTL:        30 * 24 * time.Hour,
+			SessionCleanupInterval: time.Hour,
+		},
+		OTP: OTPConfig{
+			CodeTTL:         5 * time.Minute,
+			ResendInterval:  time.Minute,
+			MaxCodesPerHour: 5,
+			MaxAttempts:     5,
+			CleanupInterval: time.Hour,
+		},
+		Phone: PhoneConfig{
+			DefaultRegion: "RU",
+		},
+		SMS: SMSConfig{
+			Backend: SMSBackendLog,
+			Timeout: 10 * time.Second,
 		},
-	}, nil
+		Media: MediaConfig{
+			Backend:        MediaBackendLocal,
+			PublicURL:      "/uploads",
+			MaxUploadSize:  10 << 20,
+			MaxPixels:      40_000_000,
+			ThumbnailSizes: []int{160, 480, 1080},
+			JPEGQuality:    85,
+			Local: LocalMediaConfig{
+				Dir: "data/uploads",
+			},
+			S3: S3Config{
+				Region: "us-east-1",
+				UseSSL: true,
+			},
+		},
+		// Минимальные параметры Argon2id из рекомендаций OWASP
+		Password: PasswordConfig{
+			Algorithm:  PasswordAlgorithmArgon2id,
+			BcryptCost: 10,
+			Argon2: Argon2Config{
+				MemoryKiB:   19 * 1024,
+				Iterations:  2,
+				Parallelism: 1,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Backend:            RateLimitBackendMemory,
+			Auth:               Rate{Requests: 60, Period: time.Minute},
+			API:                Rate{Requests: 300, Period: time.Minute},
+			SignUp:             Rate{Requests: 5, Period: time.Hour},
+			SignInIP:           Rate{Requests: 20, Period: time.Minute},
+			SignInPhone:        Rate{Requests: 5, Period: time.Minute},
+			ResetPasswordPhone: Rate{Requests: 5, Period: 15 * time.Minute},
+			LockoutThreshold:   5,
+			LockoutBase:        time.Minute,
+			LockoutMax:         time.Hour,
+			FailureWindow:      time.Hour,
+			CleanupInterval:    10 * time.Minute,
+		},
+	}
+}
+
+// LoadConfig собирает конфигурацию из нескольких источников. В порядке
+// возрастания приоритета:
+//
+//  1. значения по умолчанию;
+//  2. YAML-файл из переменной CONFIG_FILE, если она задана;
+//  3. файл .env, если он есть (не перекрывает уже заданные переменные);
+//  4. переменные окружения.
+//
+// Собранная конфигурация проверяется через Validate.
+func LoadConfig() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	cfg := defaultConfig()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadYAML(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadYAML(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	env := &envLoader{}
+
+	env.string(&cfg.Env, "APP_ENV")
+	env.string(&cfg.Log.Level, "LOG_LEVEL")
+	env.string(&cfg.Log.Format, "LOG_FORMAT")
+
+	env.string(&cfg.Server.Address, "SERVER_ADDRESS")
+	env.string(&cfg.Server.MetricsAddress, "SERVER_METRICS_ADDRESS")
+	env.duration(&cfg.Server.ReadTimeout, "SERVER_READ_TIMEOUT")
+	env.duration(&cfg.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT")
+	env.duration(&cfg.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT")
+	env.int(&cfg.Server.MaxHeaderBytes, "SERVER_MAX_HEADER_BYTES")
+	env.duration(&cfg.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT")
+	env.duration(&cfg.Server.DrainDelay, "SERVER_DRAIN_DELAY")
+	env.duration(&cfg.Server.ReadinessTimeout, "SERVER_READINESS_TIMEOUT")
+	env.strings(&cfg.Server.TrustedProxies, "SERVER_TRUSTED_PROXIES")
+
+	env.string(&cfg.DB.Host, "DB_HOST")
+	env.string(&cfg.DB.Port, "DB_PORT")
+	env.string(&cfg.DB.Username, "DB_USER")
+	env.string(&cfg.DB.Password, "DB_PASSWORD")
+	env.string(&cfg.DB.DBName, "DB_NAME")
+	env.string(&cfg.DB.SSLMode, "DB_SSLMODE")
+	env.duration(&cfg.DB.QueryTimeout, "DB_QUERY_TIMEOUT")
+	env.int(&cfg.DB.MaxOpenConns, "DB_MAX_OPEN_CONNS")
+	env.int(&cfg.DB.MaxIdleConns, "DB_MAX_IDLE_CONNS")
+	env.duration(&cfg.DB.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME")
+	env.bool(&cfg.DB.AutoMigrate, "DB_AUTO_MIGRATE")
+
+	env.string(&cfg.Auth.JWTSecret, "JWT_SECRET")
+	env.duration(&cfg.Auth.AccessTokenTTL, "ACCESS_TOKEN_TTL")
+	env.duration(&cfg.Auth.RefreshTokenTTL, "REFRESH_TOKEN_TTL")
+	env.duration(&cfg.Auth.SessionCleanupInterval, "SESSION_CLEANUP_INTERVAL")
+	env.bool(&cfg.Auth.RequirePhoneVerification, "REQUIRE_PHONE_VERIFICATION")
+
+	env.duration(&cfg.OTP.CodeTTL, "OTP_CODE_TTL")
+	env.duration(&cfg.OTP.ResendInterval, "OTP_RESEND_INTERVAL")
+	env.int(&cfg.OTP.MaxCodesPerHour, "OTP_MAX_CODES_PER_HOUR")
+	env.int(&cfg.OTP.MaxAttempts, "OTP_MAX_ATTEMPTS")
+	env.duration(&cfg.OTP.CleanupInterval, "OTP_CLEANUP_INTERVAL")
+
+	env.string(&cfg.Phone.DefaultRegion, "PHONE_DEFAULT_REGION")
+
+	env.string(&cfg.SMS.Backend, "SMS_BACKEND")
+	env.duration(&cfg.SMS.Timeout, "SMS_TIMEOUT")
+	env.string(&cfg.SMS.Twilio.AccountSID, "TWILIO_ACCOUNT_SID")
+	env.string(&cfg.SMS.Twilio.AuthToken, "TWILIO_AUTH_TOKEN")
+	env.string(&cfg.SMS.Twilio.From, "TWILIO_FROM")
+
+	env.string(&cfg.Password.Algorithm, "PASSWORD_HASH_ALGORITHM")
+	env.int(&cfg.Password.BcryptCost, "PASSWORD_BCRYPT_COST")
+	env.uint32(&cfg.Password.Argon2.MemoryKiB, "PASSWORD_ARGON2_MEMORY_KIB")
+	env.uint32(&cfg.Password.Argon2.Iterations, "PASSWORD_ARGON2_ITERATIONS")
+	env.uint8(&cfg.Password.Argon2.Parallelism, "PASSWORD_ARGON2_PARALLELISM")
+
+	env.string(&cfg.Media.Backend, "MEDIA_BACKEND")
+	env.string(&cfg.Media.PublicURL, "MEDIA_PUBLIC_URL")
+	env.int(&cfg.Media.MaxUploadSize, "MEDIA_MAX_UPLOAD_SIZE")
+	env.int(&cfg.Media.MaxPixels, "MEDIA_MAX_PIXELS")
+	env.ints(&cfg.Media.ThumbnailSizes, "MEDIA_THUMBNAIL_SIZES")
+	env.int(&cfg.Media.JPEGQuality, "MEDIA_JPEG_QUALITY")
+	env.string(&cfg.Media.Local.Dir, "MEDIA_LOCAL_DIR")
+	env.string(&cfg.Media.S3.Endpoint, "S3_ENDPOINT")
+	env.string(&cfg.Media.S3.Region, "S3_REGION")
+	env.string(&cfg.Media.S3.Bucket, "S3_BUCKET")
+	env.string(&cfg.Media.S3.AccessKey, "S3_ACCESS_KEY")
+	env.string(&cfg.Media.S3.SecretKey, "S3_SECRET_KEY")
+	env.bool(&cfg.Media.S3.UseSSL, "S3_USE_SSL")
+
+	env.string(&cfg.RateLimit.Backend, "RATE_LIMIT_BACKEND")
+	env.rate(&cfg.RateLimit.Auth, "RATE_LIMIT_AUTH")
+	env.rate(&cfg.RateLimit.API, "RATE_LIMIT_API")
+	env.rate(&cfg.RateLimit.SignUp, "RATE_LIMIT_SIGN_UP")
+	env.rate(&cfg.RateLimit.SignInIP, "RATE_LIMIT_SIGN_IN_IP")
+	env.rate(&cfg.RateLimit.SignInPhone, "RATE_LIMIT_SIGN_IN_PHONE")
+	env.rate(&cfg.RateLimit.ResetPasswordPhone, "RATE_LIMIT_RESET_PASSWORD_PHONE")
+	env.int(&cfg.RateLimit.LockoutThreshold, "SIGN_IN_LOCKOUT_THRESHOLD")
+	env.duration(&cfg.RateLimit.LockoutBase, "SIGN_IN_LOCKOUT_BASE")
+	env.duration(&cfg.RateLimit.LockoutMax, "SIGN_IN_LOCKOUT_MAX")
+	env.duration(&cfg.RateLimit.FailureWindow, "SIGN_IN_FAILURE_WINDOW")
+	env.duration(&cfg.RateLimit.CleanupInterval, "RATE_LIMIT_CLEANUP_INTERVAL")
+
+	return errors.Join(env.errs...)
 }
 
-func getEnv(key, defaultValue string) string {
+// Validate проверяет согласованность конфигурации. В production
+// дополнительно запрещены отсутствующие и слабые секреты.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Env == EnvDevelopment || c.Env == EnvProduction, "invalid env: %q", c.Env)
+	check(oneOf(c.Log.Level, "debug", "info", "warn", "error"), "invalid log level: %q", c.Log.Level)
+	check(oneOf(c.Log.Format, LogFormatJSON, LogFormatText), "invalid log format: %q", c.Log.Format)
+
+	check(c.Server.Address != "", "server address is required")
+	check(c.Server.MetricsAddress != "", "server metrics address is required")
+	check(c.Server.MetricsAddress != c.Server.Address, "server metrics address must differ from server address")
+	check(c.Server.ReadTimeout > 0, "server read timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server write timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server idle timeout must be positive")
+	check(c.Server.MaxHeaderBytes > 0, "server max header bytes must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server shutdown timeout must be positive")
+	check(c.Server.DrainDelay >= 0, "server drain delay must not be negative")
+	check(c.Server.ReadinessTimeout > 0, "server readiness timeout must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		check(isIPOrCIDR(proxy), "invalid trusted proxy: %q", proxy)
+	}
+
+	check(c.DB.Host != "", "database host is required")
+	check(c.DB.DBName != "", "database name is required")
+	check(oneOf(c.DB.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"), "invalid database sslmode: %q", c.DB.SSLMode)
+	check(c.DB.QueryTimeout > 0, "database query timeout must be positive")
+	check(c.DB.MaxOpenConns > 0, "database max open conns must be positive")
+	check(c.DB.MaxIdleConns >= 0 && c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "database max idle conns must be between 0 and max open conns")
+	check(c.DB.ConnMaxLifetime >= 0, "database conn max lifetime must not be negative")
+
+	check(c.Auth.JWTSecret != "", "jwt secret is required")
+	check(c.Auth.AccessTokenTTL > 0, "access token ttl must be positive")
+	check(c.Auth.RefreshTokenTTL > 0, "refresh token ttl must be positive")
+	check(c.Auth.SessionCleanupInterval > 0, "session cleanup interval must be positive")
+
+	check(c.OTP.CodeTTL > 0, "otp code ttl must be positive")
+	check(c.OTP.ResendInterval > 0, "otp resend interval must be positive")
+	check(c.OTP.MaxCodesPerHour > 0, "otp max codes per hour must be positive")
+	check(c.OTP.MaxAttempts > 0, "otp max attempts must be positive")
+	check(c.OTP.CleanupInterval > 0, "otp cleanup interval must be positive")
+
+	check(c.Phone.DefaultRegion != "", "phone default region is required")
+
+	check(oneOf(c.SMS.Backend, SMSBackendLog, SMSBackendTwilio), "invalid sms backend: %q", c.SMS.Backend)
+	check(c.SMS.Timeout > 0, "sms timeout must be positive")
+	if c.SMS.Backend == SMSBackendTwilio {
+		check(c.SMS.Twilio.AccountSID != "" && c.SMS.Twilio.AuthToken != "", "twilio credentials are required")
+		check(c.SMS.Twilio.From != "", "twilio sender number is required")
+	}
+
+	check(oneOf(c.Password.Algorithm, PasswordAlgorithmArgon2id, PasswordAlgorithmBcrypt), "invalid password hash algorithm: %q", c.Password.Algorithm)
+	check(c.Password.BcryptCost >= 4 && c.Password.BcryptCost <= 31, "password bcrypt cost must be between 4 and 31")
+	check(c.Password.Argon2.MemoryKiB >= 8*uint32(c.Password.Argon2.Parallelism), "password argon2 memory must be at least 8 KiB per thread")
+	check(c.Password.Argon2.Iterations > 0, "password argon2 iterations must be positive")
+	check(c.Password.Argon2.Parallelism > 0, "password argon2 parallelism must be positive")
+
+	check(oneOf(c.RateLimit.Backend, RateLimitBackendMemory, RateLimitBackendPostgres), "invalid rate limit backend: %q", c.RateLimit.Backend)
+	check(c.RateLimit.Auth.valid(), "invalid auth rate limit: %s", c.RateLimit.Auth)
+	check(c.RateLimit.API.valid(), "invalid api rate limit: %s", c.RateLimit.API)
+	check(c.RateLimit.SignUp.valid(), "invalid sign up rate limit: %s", c.RateLimit.SignUp)
+	check(c.RateLimit.SignInIP.valid(), "invalid sign in ip rate limit: %s", c.RateLimit.SignInIP)
+	check(c.RateLimit.SignInPhone.valid(), "invalid sign in phone rate limit: %s", c.RateLimit.SignInPhone)
+	check(c.RateLimit.ResetPasswordPhone.valid(), "invalid reset password phone rate limit: %s", c.RateLimit.ResetPasswordPhone)
+	check(c.RateLimit.LockoutThreshold > 0, "sign in lockout threshold must be positive")
+	check(c.RateLimit.LockoutBase > 0, "sign in lockout base must be positive")
+	check(c.RateLimit.LockoutMax >= c.RateLimit.LockoutBase, "sign in lockout max must not be less than lockout base")
+	check(c.RateLimit.FailureWindow > 0, "sign in failure window must be positive")
+	check(c.RateLimit.CleanupInterval > 0, "rate limit cleanup interval must be positive")
+
+	check(oneOf(c.Media.Backend, MediaBackendLocal, MediaBackendS3), "invalid media backend: %q", c.Media.Backend)
+	check(c.Media.PublicURL != "", "media public url is required")
+	check(c.Media.MaxUploadSize > 0, "media max upload size must be positive")
+	check(c.Media.MaxPixels > 0, "media max pixels must be positive")
+	check(c.Media.JPEGQuality >= 1 && c.Media.JPEGQuality <= 100, "media jpeg quality must be between 1 and 100")
+	for _, size := range c.Media.ThumbnailSizes {
+		check(size > 0, "media thumbnail sizes must be positive")
+	}
+	switch c.Media.Backend {
+	case MediaBackendLocal:
+		check(c.Media.Local.Dir != "", "media local dir is required")
+	case MediaBackendS3:
+		check(c.Media.S3.Endpoint != "", "s3 endpoint is required")
+		check(c.Media.S3.Bucket != "", "s3 bucket is required")
+		check(c.Media.S3.AccessKey != "" && c.Media.S3.SecretKey != "", "s3 credentials are required")
+	}
+
+	if c.Env == EnvProduction {
+		check(len(c.Auth.JWTSecret) >= minJWTSecretLength, "jwt secret must be at least %d characters in production", minJWTSecretLength)
+		check(!isWeakSecret(c.Auth.JWTSecret), "jwt secret is too weak for production")
+		check(c.DB.Password != "", "database password is required in production")
+		check(!isWeakSecret(c.DB.Password), "database password is too weak for production")
+		check(c.SMS.Backend != SMSBackendLog, "sms gateway is required in production: log backend does not deliver codes")
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+func isWeakSecret(secret string) bool {
+	return weakSecrets[strings.ToLower(secret)]
+}
+
+func isIPOrCIDR(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, v := range allowed {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// envLoader перекрывает значения конфигурации заданными переменными
+// окружения и накапливает ошибки разбора
+type envLoader struct {
+	errs []error
+}
+
+func (l *envLoader) string(dst *string, key string) {
 	if value := os.Getenv(key); value != "" {
-		return value
+		*dst = value
+	}
+}
+
+func (l *envLoader) duration(dst *time.Duration, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
 	}
-	return defaultValue
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = duration
+}
+
+func (l *envLoader) int(dst *int, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = number
+}
+
+func (l *envLoader) uint32(dst *uint32, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	number, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = uint32(number)
+}
+
+func (l *envLoader) uint8(dst *uint8, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	number, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = uint8(number)
+}
+
+// strings разбирает список строк через запятую: "10.0.0.0/8,192.168.1.1"
+func (l *envLoader) strings(dst *[]string, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+
+	*dst = values
+}
+
+// ints разбирает список чисел через запятую: "160,480,1080"
+func (l *envLoader) ints(dst *[]int, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	var numbers []int
+	for _, part := range strings.Split(value, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+			return
+		}
+		numbers = append(numbers, number)
+	}
+
+	*dst = numbers
+}
+
+func (l *envLoader) bool(dst *bool, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = b
+}
+
+func (l *envLoader) rate(dst *Rate, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	var rate Rate
+	if err := rate.UnmarshalText([]byte(value)); err != nil {
+		l.errs = append(l.errs, fmt.Errorf("invalid %s: %q", key, value))
+		return
+	}
+
+	*dst = rate
 }
@@ -2,10 +2,20 @@ package auth
 
 import "time"
 
+// Session описывает выданный refresh token. Сам токен не хранится, только
+// его хеш. Все сессии, полученные ротацией одного токена, имеют общий
+// FamilyID; Consumed помечает уже обмененные. Семейство соответствует
+// одному устройству пользователя.
 type Session struct {
-	ID           int       `db:"id"`
-	UserID       int       `db:"user_id"`
-	RefreshToken string    `db:"refresh_token"`
-	ExpiresAt    time.Time `db:"expires_at"`
-	CreatedAt    time.Time `db:"created_at"`
+	ID               int       `db:"id"`
+	UserID           int       `db:"user_id"`
+	RefreshTokenHash string    `db:"refresh_token_hash"`
+	FamilyID         string    `db:"family_id"`
+	Consumed         bool      `db:"consumed"`
+	DeviceName       string    `db:"device_name"`
+	UserAgent        string    `db:"user_agent"`
+	IP               string    `db:"ip"`
+	ExpiresAt        time.Time `db:"expires_at"`
+	CreatedAt        time.Time `db:"created_at"`
+	LastUsedAt       time.Time `db:"last_used_at"`
 }
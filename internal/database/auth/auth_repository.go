@@ -1,87 +1,256 @@
 package auth
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
 )
 
+// ErrSessionConsumed возвращается при попытке повторно обменять refresh token
+var ErrSessionConsumed = errors.New("session already consumed")
+
+// ErrSessionNotFound возвращается, если сессии нет или она уже удалена
+var ErrSessionNotFound = apperror.NotFound("session_not_found", "session not found")
+
 type AuthRepository interface {
-	CreateSession(session *Session) error
-	GetSessionByRefreshToken(refreshToken string) (*Session, error)
-	DeleteSession(refreshToken string) error
-	DeleteUserSessions(userID int) error
+	CreateSession(ctx context.Context, session *Session) error
+	GetSessionByID(ctx context.Context, id int) (*Session, error)
+	GetSessionByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*Session, error)
+	ListActiveUserSessions(ctx context.Context, userID int, now time.Time) ([]*Session, error)
+	RotateSession(ctx context.Context, oldSessionID int, newSession *Session) error
+	DeleteSessionFamily(ctx context.Context, familyID string) error
+	DeleteUserSessions(ctx context.Context, userID int) error
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
 }
 
 type AuthRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
 }
 
-func NewAuthRepositoryImpl(db *sql.DB) AuthRepository {
-	return &AuthRepositoryImpl{db: db}
+func NewAuthRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) AuthRepository {
+	return &AuthRepositoryImpl{db: db, timeout: timeout, logger: logger}
 }
 
-func (r *AuthRepositoryImpl) CreateSession(session *Session) error {
-	query := `
-        INSERT INTO sessions (user_id, refresh_token, expires_at, created_at)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id`
+const sessionColumns = `id, user_id, refresh_token_hash, family_id, consumed, device_name, user_agent, ip, expires_at, created_at, last_used_at`
 
-	return r.db.QueryRow(
-		query,
-		session.UserID,
-		session.RefreshToken,
-		session.ExpiresAt,
-		time.Now(),
-	).Scan(&session.ID)
+// CreateSession создает сессию. Если FamilyID не задан, сессия начинает
+// новое семейство.
+func (r *AuthRepositoryImpl) CreateSession(ctx context.Context, session *Session) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := createSession(ctx, r.db, session)
+	return database.LogError(ctx, r.logger, "auth.CreateSession", err)
 }
 
-func (r *AuthRepositoryImpl) GetSessionByRefreshToken(refreshToken string) (*Session, error) {
-	session := &Session{}
+func (r *AuthRepositoryImpl) GetSessionByID(ctx context.Context, id int) (*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
 	query := `
-        SELECT id, user_id, refresh_token, expires_at, created_at
+        SELECT ` + sessionColumns + `
         FROM sessions
-        WHERE refresh_token = $1`
+        WHERE id = $1`
 
-	err := r.db.QueryRow(query, refreshToken).Scan(
-		&session.ID,
-		&session.UserID,
-		&session.RefreshToken,
-		&session.ExpiresAt,
-		&session.CreatedAt,
-	)
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "auth.GetSessionByID", err)
+	}
+
+	return session, nil
+}
+
+func (r *AuthRepositoryImpl) GetSessionByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + sessionColumns + `
+        FROM sessions
+        WHERE refresh_token_hash = $1`
 
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, refreshTokenHash))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("session not found")
+			return nil, ErrSessionNotFound
 		}
-		return nil, err
+		return nil, database.LogError(ctx, r.logger, "auth.GetSessionByRefreshTokenHash", err)
 	}
 
 	return session, nil
 }
 
-func (r *AuthRepositoryImpl) DeleteSession(refreshToken string) error {
-	query := `DELETE FROM sessions WHERE refresh_token = $1`
-	result, err := r.db.Exec(query, refreshToken)
+// ListActiveUserSessions возвращает по одной действующей сессии на каждое
+// устройство пользователя
+func (r *AuthRepositoryImpl) ListActiveUserSessions(ctx context.Context, userID int, now time.Time) ([]*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + sessionColumns + `
+        FROM sessions
+        WHERE user_id = $1 AND NOT consumed AND expires_at > $2
+        ORDER BY last_used_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, now)
 	if err != nil {
-		return err
+		return nil, database.LogError(ctx, r.logger, "auth.ListActiveUserSessions", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	sessions := make([]*Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "auth.ListActiveUserSessions", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, database.LogError(ctx, r.logger, "auth.ListActiveUserSessions", rows.Err())
+}
+
+// RotateSession в одной транзакции помечает старую сессию обмененной и
+// создает новую в том же семействе. Новая сессия наследует время создания
+// и, если не задано иное, название устройства. Если старая сессия уже была
+// обменена (в том числе параллельным запросом), возвращает ErrSessionConsumed.
+func (r *AuthRepositoryImpl) RotateSession(ctx context.Context, oldSessionID int, newSession *Session) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return database.LogError(ctx, r.logger, "auth.RotateSession", err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return errors.New("session not found")
+	query := `
+        UPDATE sessions
+        SET consumed = TRUE,
+            updated_at = $2
+        WHERE id = $1 AND NOT consumed
+        RETURNING family_id, device_name, created_at`
+
+	var deviceName string
+	err = tx.QueryRowContext(ctx, query, oldSessionID, time.Now()).Scan(
+		&newSession.FamilyID,
+		&deviceName,
+		&newSession.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSessionConsumed
+		}
+		return database.LogError(ctx, r.logger, "auth.RotateSession", err)
+	}
+
+	if newSession.DeviceName == "" {
+		newSession.DeviceName = deviceName
 	}
 
-	return nil
+	if err := createSession(ctx, tx, newSession); err != nil {
+		return database.LogError(ctx, r.logger, "auth.RotateSession", err)
+	}
+
+	return database.LogError(ctx, r.logger, "auth.RotateSession", tx.Commit())
 }
 
-func (r *AuthRepositoryImpl) DeleteUserSessions(userID int) error {
+func (r *AuthRepositoryImpl) DeleteSessionFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM sessions WHERE family_id = $1`
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return database.LogError(ctx, r.logger, "auth.DeleteSessionFamily", err)
+}
+
+func (r *AuthRepositoryImpl) DeleteUserSessions(ctx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
 	query := `DELETE FROM sessions WHERE user_id = $1`
-	_, err := r.db.Exec(query, userID)
-	return err
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return database.LogError(ctx, r.logger, "auth.DeleteUserSessions", err)
+}
+
+// DeleteExpiredSessions удаляет истекшие сессии и возвращает их количество
+func (r *AuthRepositoryImpl) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM sessions WHERE expires_at <= $1`
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, database.LogError(ctx, r.logger, "auth.DeleteExpiredSessions", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	return deleted, database.LogError(ctx, r.logger, "auth.DeleteExpiredSessions", err)
+}
+
+// queryRower позволяет выполнять запросы как через *sql.DB, так и в транзакции
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func createSession(ctx context.Context, q queryRower, session *Session) error {
+	query := `
+        INSERT INTO sessions (user_id, refresh_token_hash, family_id, device_name, user_agent, ip, expires_at, created_at, last_used_at)
+        VALUES ($1, $2, COALESCE(NULLIF($3, '')::uuid, gen_random_uuid()), $4, $5, $6, $7, $8, $9)
+        RETURNING id, family_id`
+
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	session.LastUsedAt = now
+
+	return q.QueryRowContext(ctx,
+		query,
+		session.UserID,
+		session.RefreshTokenHash,
+		session.FamilyID,
+		session.DeviceName,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+		session.CreatedAt,
+		session.LastUsedAt,
+	).Scan(&session.ID, &session.FamilyID)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (*Session, error) {
+	session := &Session{}
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenHash,
+		&session.FamilyID,
+		&session.Consumed,
+		&session.DeviceName,
+		&session.UserAgent,
+		&session.IP,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
 }
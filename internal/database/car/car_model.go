@@ -0,0 +1,19 @@
+package car
+
+import "time"
+
+type Car struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	Make       string    `db:"make"`
+	Model      string    `db:"model"`
+	Generation string    `db:"generation"`
+	Year       int       `db:"year"`
+	VIN        string    `db:"vin"`
+	Color      string    `db:"color"`
+	Engine     string    `db:"engine"`
+	Mileage    int       `db:"mileage"`
+	IsPrimary  bool      `db:"is_primary"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
@@ -0,0 +1,285 @@
+package car
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/lib/pq"
+)
+
+// ErrCarNotFound возвращается, если машины с указанным ID нет
+var ErrCarNotFound = apperror.NotFound("car_not_found", "car not found")
+
+// ErrPrimaryCarConflict возвращается, если параллельный запрос успел назначить
+// пользователю другую основную машину
+var ErrPrimaryCarConflict = apperror.Conflict("primary_car_conflict", "another car was made primary concurrently")
+
+type CarRepository interface {
+	// Create сохраняет машину вместе с фотографиями; порядок photoIDs сохраняется
+	Create(ctx context.Context, car *Car, photoIDs []int) error
+	GetByID(ctx context.Context, id int) (*Car, error)
+	ListByUser(ctx context.Context, userID int) ([]*Car, error)
+	// Update сохраняет машину и заменяет ее фотографии; при photoIDs == nil
+	// фотографии не меняются
+	Update(ctx context.Context, car *Car, photoIDs []int) error
+	Delete(ctx context.Context, id int) error
+}
+
+type CarRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewCarRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) CarRepository {
+	return &CarRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+const carColumns = `id, user_id, make, model, generation, year, vin, color, engine, mileage, is_primary, created_at, updated_at`
+
+func (r *CarRepositoryImpl) Create(ctx context.Context, car *Car, photoIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "car.Create", err)
+	}
+	defer tx.Rollback()
+
+	if car.IsPrimary {
+		if err := resetPrimary(ctx, tx, car.UserID); err != nil {
+			return database.LogError(ctx, r.logger, "car.Create", err)
+		}
+	}
+
+	query := `
+        INSERT INTO cars (user_id, make, model, generation, year, vin, color, engine, mileage, is_primary, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+        RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, query,
+		car.UserID,
+		car.Make,
+		car.Model,
+		car.Generation,
+		car.Year,
+		car.VIN,
+		car.Color,
+		car.Engine,
+		car.Mileage,
+		car.IsPrimary,
+		now,
+	).Scan(&car.ID, &car.CreatedAt, &car.UpdatedAt)
+
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return ErrPrimaryCarConflict
+		}
+		return database.LogError(ctx, r.logger, "car.Create", err)
+	}
+
+	if len(photoIDs) > 0 {
+		if err := setPhotos(ctx, tx, car.ID, photoIDs); err != nil {
+			return database.LogError(ctx, r.logger, "car.Create", err)
+		}
+	}
+
+	return database.LogError(ctx, r.logger, "car.Create", tx.Commit())
+}
+
+func (r *CarRepositoryImpl) GetByID(ctx context.Context, id int) (*Car, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + carColumns + `
+        FROM cars
+        WHERE id = $1`
+
+	car, err := scanCar(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCarNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "car.GetByID", err)
+	}
+
+	return car, nil
+}
+
+func (r *CarRepositoryImpl) ListByUser(ctx context.Context, userID int) ([]*Car, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + carColumns + `
+        FROM cars
+        WHERE user_id = $1
+        ORDER BY is_primary DESC, created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "car.ListByUser", err)
+	}
+	defer rows.Close()
+
+	cars := make([]*Car, 0)
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "car.ListByUser", err)
+		}
+		cars = append(cars, car)
+	}
+
+	return cars, database.LogError(ctx, r.logger, "car.ListByUser", rows.Err())
+}
+
+func (r *CarRepositoryImpl) Update(ctx context.Context, car *Car, photoIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "car.Update", err)
+	}
+	defer tx.Rollback()
+
+	if car.IsPrimary {
+		if err := resetPrimary(ctx, tx, car.UserID); err != nil {
+			return database.LogError(ctx, r.logger, "car.Update", err)
+		}
+	}
+
+	query := `
+        UPDATE cars
+        SET make = $1,
+            model = $2,
+            generation = $3,
+            year = $4,
+            vin = $5,
+            color = $6,
+            engine = $7,
+            mileage = $8,
+            is_primary = $9,
+            updated_at = $10
+        WHERE id = $11
+        RETURNING created_at, updated_at`
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, query,
+		car.Make,
+		car.Model,
+		car.Generation,
+		car.Year,
+		car.VIN,
+		car.Color,
+		car.Engine,
+		car.Mileage,
+		car.IsPrimary,
+		now,
+		car.ID,
+	).Scan(&car.CreatedAt, &car.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrCarNotFound
+		}
+		if database.IsUniqueViolation(err) {
+			return ErrPrimaryCarConflict
+		}
+		return database.LogError(ctx, r.logger, "car.Update", err)
+	}
+
+	if photoIDs != nil {
+		if err := setPhotos(ctx, tx, car.ID, photoIDs); err != nil {
+			return database.LogError(ctx, r.logger, "car.Update", err)
+		}
+	}
+
+	return database.LogError(ctx, r.logger, "car.Update", tx.Commit())
+}
+
+func (r *CarRepositoryImpl) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM cars WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "car.Delete", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "car.Delete", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCarNotFound
+	}
+
+	return nil
+}
+
+// resetPrimary снимает флаг основной машины со всех машин пользователя
+func resetPrimary(ctx context.Context, tx *sql.Tx, userID int) error {
+	query := `UPDATE cars SET is_primary = FALSE WHERE user_id = $1 AND is_primary`
+	_, err := tx.ExecContext(ctx, query, userID)
+	return err
+}
+
+// setPhotos заменяет фотографии машины внутри транзакции записи машины
+func setPhotos(ctx context.Context, tx *sql.Tx, carID int, photoIDs []int) error {
+	query := `DELETE FROM car_photos WHERE car_id = $1`
+	if _, err := tx.ExecContext(ctx, query, carID); err != nil {
+		return err
+	}
+
+	if len(photoIDs) == 0 {
+		return nil
+	}
+
+	// WITH ORDINALITY нумерует элементы массива, сохраняя порядок
+	query = `
+        INSERT INTO car_photos (car_id, media_id, position)
+        SELECT $1, media_id, position
+        FROM unnest($2::INTEGER[]) WITH ORDINALITY AS t(media_id, position)`
+
+	_, err := tx.ExecContext(ctx, query, carID, pq.Array(photoIDs))
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCar(row rowScanner) (*Car, error) {
+	car := &Car{}
+	err := row.Scan(
+		&car.ID,
+		&car.UserID,
+		&car.Make,
+		&car.Model,
+		&car.Generation,
+		&car.Year,
+		&car.VIN,
+		&car.Color,
+		&car.Engine,
+		&car.Mileage,
+		&car.IsPrimary,
+		&car.CreatedAt,
+		&car.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return car, nil
+}
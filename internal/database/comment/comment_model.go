@@ -0,0 +1,29 @@
+package comment
+
+import "time"
+
+type Comment struct {
+	ID     int `db:"id"`
+	PostID int `db:"post_id"`
+	UserID int `db:"user_id"`
+	// ParentID - комментарий верхнего уровня, на который дан ответ
+	ParentID   *int      `db:"parent_id"`
+	Body       string    `db:"body"`
+	ReplyCount int       `db:"reply_count"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Cursor указывает на последний полученный комментарий при keyset-пагинации
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// ListFilter задает параметры выборки комментариев поста
+type ListFilter struct {
+	// ParentID выбирает ответы на комментарий; nil - комментарии верхнего уровня
+	ParentID *int
+	Cursor   *Cursor
+	Limit    int
+}
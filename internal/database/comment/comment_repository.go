@@ -0,0 +1,312 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/NikitaBelov-mobile/car-social/internal/database/post"
+)
+
+var (
+	// ErrCommentNotFound возвращается, если комментария с указанным ID нет
+	ErrCommentNotFound = apperror.NotFound("comment_not_found", "comment not found")
+	// ErrParentNotFound возвращается, если комментарий, на который отвечают,
+	// не найден в посте или сам является ответом
+	ErrParentNotFound = apperror.InvalidInput("parent_comment_not_found", "parent comment not found")
+)
+
+// CommentRepository хранит комментарии к постам. Create и Delete в той же
+// транзакции обновляют счетчик комментариев поста и счетчик ответов
+// родительского комментария.
+type CommentRepository interface {
+	Create(ctx context.Context, comment *Comment) error
+	GetByID(ctx context.Context, id int) (*Comment, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, comment *Comment) error
+	ListByPost(ctx context.Context, postID int, filter ListFilter) ([]*Comment, error)
+}
+
+type CommentRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewCommentRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) CommentRepository {
+	return &CommentRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+const commentColumns = `id, post_id, user_id, parent_id, body, reply_count, created_at, updated_at`
+
+// Create добавляет комментарий. Строка поста блокируется первой, как и в
+// Delete, поэтому параллельные изменения комментариев одного поста
+// выполняются по очереди и не приводят к взаимной блокировке.
+func (r *CommentRepositoryImpl) Create(ctx context.Context, comment *Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "comment.Create", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE posts
+        SET comment_count = comment_count + 1
+        WHERE id = $1`
+
+	if err := execOne(ctx, tx, post.ErrPostNotFound, query, comment.PostID); err != nil {
+		return r.createError(ctx, err)
+	}
+
+	if comment.ParentID != nil {
+		query = `
+        UPDATE comments
+        SET reply_count = reply_count + 1
+        WHERE id = $1 AND post_id = $2 AND parent_id IS NULL`
+
+		if err := execOne(ctx, tx, ErrParentNotFound, query, *comment.ParentID, comment.PostID); err != nil {
+			return r.createError(ctx, err)
+		}
+	}
+
+	query = `
+        INSERT INTO comments (post_id, user_id, parent_id, body, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $5)
+        RETURNING id, reply_count, created_at, updated_at`
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, query,
+		comment.PostID,
+		comment.UserID,
+		comment.ParentID,
+		comment.Body,
+		now,
+	).Scan(&comment.ID, &comment.ReplyCount, &comment.CreatedAt, &comment.UpdatedAt)
+
+	if err != nil {
+		return database.LogError(ctx, r.logger, "comment.Create", err)
+	}
+
+	return database.LogError(ctx, r.logger, "comment.Create", tx.Commit())
+}
+
+func (r *CommentRepositoryImpl) GetByID(ctx context.Context, id int) (*Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + commentColumns + `
+        FROM comments
+        WHERE id = $1`
+
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCommentNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "comment.GetByID", err)
+	}
+
+	return comment, nil
+}
+
+func (r *CommentRepositoryImpl) Update(ctx context.Context, comment *Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE comments
+        SET body = $1,
+            updated_at = $2
+        WHERE id = $3
+        RETURNING reply_count, created_at, updated_at`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		comment.Body,
+		now,
+		comment.ID,
+	).Scan(&comment.ReplyCount, &comment.CreatedAt, &comment.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrCommentNotFound
+		}
+		return database.LogError(ctx, r.logger, "comment.Update", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет комментарий вместе с ответами на него и уменьшает счетчики
+func (r *CommentRepositoryImpl) Delete(ctx context.Context, comment *Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "comment.Delete", err)
+	}
+	defer tx.Rollback()
+
+	// Пока строка поста заблокирована, новые ответы не появятся и
+	// reply_count удаляемого комментария останется точным
+	query := `SELECT id FROM posts WHERE id = $1 FOR UPDATE`
+
+	var postID int
+	if err := tx.QueryRowContext(ctx, query, comment.PostID).Scan(&postID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrCommentNotFound
+		}
+		return database.LogError(ctx, r.logger, "comment.Delete", err)
+	}
+
+	query = `
+        DELETE FROM comments
+        WHERE id = $1
+        RETURNING parent_id, reply_count`
+
+	var parentID sql.NullInt64
+	var replyCount int
+	if err := tx.QueryRowContext(ctx, query, comment.ID).Scan(&parentID, &replyCount); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrCommentNotFound
+		}
+		return database.LogError(ctx, r.logger, "comment.Delete", err)
+	}
+
+	query = `
+        UPDATE posts
+        SET comment_count = comment_count - $1
+        WHERE id = $2`
+
+	if _, err := tx.ExecContext(ctx, query, 1+replyCount, postID); err != nil {
+		return database.LogError(ctx, r.logger, "comment.Delete", err)
+	}
+
+	if parentID.Valid {
+		query = `
+        UPDATE comments
+        SET reply_count = reply_count - 1
+        WHERE id = $1`
+
+		if _, err := tx.ExecContext(ctx, query, parentID.Int64); err != nil {
+			return database.LogError(ctx, r.logger, "comment.Delete", err)
+		}
+	}
+
+	return database.LogError(ctx, r.logger, "comment.Delete", tx.Commit())
+}
+
+// ListByPost возвращает комментарии поста в хронологическом порядке
+// с keyset-пагинацией по (created_at, id)
+func (r *CommentRepositoryImpl) ListByPost(ctx context.Context, postID int, filter ListFilter) ([]*Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	args := []any{postID}
+	query := `
+        SELECT ` + commentColumns + `
+        FROM comments
+        WHERE post_id = $1`
+
+	if filter.ParentID != nil {
+		args = append(args, *filter.ParentID)
+		query += fmt.Sprintf(` AND parent_id = $%d`, len(args))
+	} else {
+		query += ` AND parent_id IS NULL`
+	}
+
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.CreatedAt, filter.Cursor.ID)
+		query += fmt.Sprintf(` AND (created_at, id) > ($%d, $%d)`, len(args)-1, len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(`
+        ORDER BY created_at, id
+        LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "comment.ListByPost", err)
+	}
+	defer rows.Close()
+
+	comments := make([]*Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "comment.ListByPost", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, database.LogError(ctx, r.logger, "comment.ListByPost", rows.Err())
+}
+
+// createError пишет в лог ошибки базы, не трогая ошибки отсутствия поста
+// или родительского комментария
+func (r *CommentRepositoryImpl) createError(ctx context.Context, err error) error {
+	if errors.Is(err, apperror.ErrNotFound) || errors.Is(err, apperror.ErrInvalidInput) {
+		return err
+	}
+	return database.LogError(ctx, r.logger, "comment.Create", err)
+}
+
+// execOne выполняет запрос, который должен изменить ровно одну строку,
+// и возвращает notFound, если строка не найдена
+func execOne(ctx context.Context, tx *sql.Tx, notFound error, query string, args ...any) error {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanComment(row rowScanner) (*Comment, error) {
+	comment := &Comment{}
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.UserID,
+		&parentID,
+		&comment.Body,
+		&comment.ReplyCount,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+// queryCanceled - код ошибки Postgres при отмене запроса (в том числе
+// по statement_timeout)
+const queryCanceled = "57014"
+
+// IsUnavailable сообщает, что запрос не выполнен из-за недоступности базы:
+// истек таймаут, запрос был отменен или соединение потеряно
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == queryCanceled {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// uniqueViolation - код ошибки Postgres при нарушении ограничения уникальности
+const uniqueViolation = "23505"
+
+// IsUniqueViolation сообщает, что запрос нарушил ограничение уникальности
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// foreignKeyViolation - код ошибки Postgres при нарушении внешнего ключа
+const foreignKeyViolation = "23503"
+
+// IsForeignKeyViolation сообщает, что запрос сослался на несуществующую строку
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation
+}
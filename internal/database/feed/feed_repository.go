@@ -0,0 +1,41 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/database/post"
+)
+
+// FeedRepository отдает домашнюю ленту пользователя.
+//
+// Интерфейс не зависит от способа построения ленты: текущая реализация
+// собирает ее при чтении (fan-out-on-read), а реализация с fan-out-on-write
+// сможет читать заранее разложенные по лентам записи, получая уведомления
+// о новых и удаленных постах через PostPublished и PostDeleted.
+type FeedRepository interface {
+	GetHomeFeed(ctx context.Context, userID int, filter post.ListFilter) ([]*post.Post, error)
+	PostPublished(ctx context.Context, p *post.Post) error
+	PostDeleted(ctx context.Context, p *post.Post) error
+}
+
+type FeedRepositoryImpl struct {
+	postRepo post.PostRepository
+}
+
+func NewFeedRepositoryImpl(postRepo post.PostRepository) FeedRepository {
+	return &FeedRepositoryImpl{postRepo: postRepo}
+}
+
+func (r *FeedRepositoryImpl) GetHomeFeed(ctx context.Context, userID int, filter post.ListFilter) ([]*post.Post, error) {
+	return r.postRepo.ListByFollowedAuthors(ctx, userID, filter)
+}
+
+// PostPublished ничего не делает: при fan-out-on-read лента строится при чтении
+func (r *FeedRepositoryImpl) PostPublished(_ context.Context, _ *post.Post) error {
+	return nil
+}
+
+// PostDeleted ничего не делает: при fan-out-on-read лента строится при чтении
+func (r *FeedRepositoryImpl) PostDeleted(_ context.Context, _ *post.Post) error {
+	return nil
+}
@@ -0,0 +1,21 @@
+package follow
+
+import "time"
+
+type Follow struct {
+	FollowerID int       `db:"follower_id"`
+	FolloweeID int       `db:"followee_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Cursor указывает на последнюю полученную подписку при keyset-пагинации
+type Cursor struct {
+	CreatedAt time.Time
+	UserID    int
+}
+
+// Counts содержит количество подписчиков и подписок пользователя
+type Counts struct {
+	Followers int
+	Following int
+}
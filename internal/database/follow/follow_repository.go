@@ -0,0 +1,122 @@
+package follow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+type FollowRepository interface {
+	Follow(ctx context.Context, followerID, followeeID int) error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	ListFollowers(ctx context.Context, userID int, cursor *Cursor, limit int) ([]*Follow, error)
+	ListFollowing(ctx context.Context, userID int, cursor *Cursor, limit int) ([]*Follow, error)
+	GetCounts(ctx context.Context, userID int) (*Counts, error)
+}
+
+type FollowRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewFollowRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) FollowRepository {
+	return &FollowRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+// Follow создает подписку; повторная подписка не считается ошибкой
+func (r *FollowRepositoryImpl) Follow(ctx context.Context, followerID, followeeID int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        INSERT INTO follows (follower_id, followee_id, created_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (follower_id, followee_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID, time.Now())
+	return database.LogError(ctx, r.logger, "follow.Follow", err)
+}
+
+// Unfollow удаляет подписку; отсутствие подписки не считается ошибкой
+func (r *FollowRepositoryImpl) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return database.LogError(ctx, r.logger, "follow.Unfollow", err)
+}
+
+func (r *FollowRepositoryImpl) ListFollowers(ctx context.Context, userID int, cursor *Cursor, limit int) ([]*Follow, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	follows, err := r.list(ctx, "followee_id", "follower_id", userID, cursor, limit)
+	return follows, database.LogError(ctx, r.logger, "follow.ListFollowers", err)
+}
+
+func (r *FollowRepositoryImpl) ListFollowing(ctx context.Context, userID int, cursor *Cursor, limit int) ([]*Follow, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	follows, err := r.list(ctx, "follower_id", "followee_id", userID, cursor, limit)
+	return follows, database.LogError(ctx, r.logger, "follow.ListFollowing", err)
+}
+
+func (r *FollowRepositoryImpl) GetCounts(ctx context.Context, userID int) (*Counts, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	counts := &Counts{}
+	query := `
+        SELECT
+            (SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+            (SELECT COUNT(*) FROM follows WHERE follower_id = $1)`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&counts.Followers, &counts.Following)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "follow.GetCounts", err)
+	}
+
+	return counts, nil
+}
+
+// list выбирает подписки по ключевой колонке keyColumn в обратном
+// хронологическом порядке с keyset-пагинацией по (created_at, otherColumn)
+func (r *FollowRepositoryImpl) list(ctx context.Context, keyColumn, otherColumn string, userID int, cursor *Cursor, limit int) ([]*Follow, error) {
+	args := []any{userID, limit}
+	cursorCond := ""
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.UserID)
+		cursorCond = fmt.Sprintf("AND (created_at, %s) < ($3, $4)", otherColumn)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT follower_id, followee_id, created_at
+        FROM follows
+        WHERE %s = $1 %s
+        ORDER BY created_at DESC, %s DESC
+        LIMIT $2`, keyColumn, cursorCond, otherColumn)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := make([]*Follow, 0)
+	for rows.Next() {
+		follow := &Follow{}
+		if err := rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// LogError пишет в лог ошибку операции op и возвращает ее без изменений.
+// ID запроса и пользователя добавляются логгером из ctx. Отмена запроса
+// клиентом не считается ошибкой базы и пишется с уровнем warn.
+func LogError(ctx context.Context, log *slog.Logger, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	level := slog.LevelError
+	if errors.Is(err, context.Canceled) {
+		level = slog.LevelWarn
+	}
+
+	log.Log(ctx, level, "database query failed", "op", op, "error", err)
+	return err
+}
@@ -0,0 +1,37 @@
+package media
+
+import "time"
+
+// Media - загруженное изображение. Файлы хранятся в MediaStore, в базе
+// только их ключи и метаданные.
+type Media struct {
+	ID          int    `db:"id"`
+	OwnerID     int    `db:"owner_id"`
+	StorageKey  string `db:"storage_key"`
+	ContentType string `db:"content_type"`
+	Size        int64  `db:"size_bytes"`
+	Width       int    `db:"width"`
+	Height      int    `db:"height"`
+	// Checksum - SHA-256 сохраненного файла в hex
+	Checksum   string      `db:"checksum"`
+	Thumbnails []Thumbnail `db:"thumbnails"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+// Thumbnail - уменьшенная копия изображения
+type Thumbnail struct {
+	// Size - размер по длинной стороне, под который строилась миниатюра
+	Size       int    `json:"size"`
+	StorageKey string `json:"storage_key"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+// Keys возвращает ключи всех файлов изображения, включая миниатюры
+func (m *Media) Keys() []string {
+	keys := []string{m.StorageKey}
+	for _, thumbnail := range m.Thumbnails {
+		keys = append(keys, thumbnail.StorageKey)
+	}
+	return keys
+}
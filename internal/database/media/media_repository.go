@@ -0,0 +1,264 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/lib/pq"
+)
+
+var ErrMediaNotFound = apperror.NotFound("media_not_found", "media not found")
+
+type MediaRepository interface {
+	Create(ctx context.Context, media *Media) error
+	GetByID(ctx context.Context, id int) (*Media, error)
+	// GetByIDs возвращает найденные изображения; отсутствующие ID пропускаются
+	GetByIDs(ctx context.Context, ids []int) ([]*Media, error)
+	Delete(ctx context.Context, id int) error
+
+	// SetPostMedia заменяет изображения поста; порядок mediaIDs сохраняется
+	SetPostMedia(ctx context.Context, postID int, mediaIDs []int) error
+	ListPostMedia(ctx context.Context, postIDs []int) (map[int][]*Media, error)
+	ListCarPhotos(ctx context.Context, carIDs []int) (map[int][]*Media, error)
+}
+
+type MediaRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewMediaRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) MediaRepository {
+	return &MediaRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+const mediaColumns = `m.id, m.owner_id, m.storage_key, m.content_type, m.size_bytes,
+               m.width, m.height, m.checksum, m.thumbnails, m.created_at`
+
+func (r *MediaRepositoryImpl) Create(ctx context.Context, media *Media) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	thumbnails, err := json.Marshal(media.Thumbnails)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO media (owner_id, storage_key, content_type, size_bytes, width, height, checksum, thumbnails, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        RETURNING id, created_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		media.OwnerID,
+		media.StorageKey,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+		media.Checksum,
+		thumbnails,
+		time.Now(),
+	).Scan(&media.ID, &media.CreatedAt)
+
+	return database.LogError(ctx, r.logger, "media.Create", err)
+}
+
+func (r *MediaRepositoryImpl) GetByID(ctx context.Context, id int) (*Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + mediaColumns + `
+        FROM media m
+        WHERE m.id = $1`
+
+	media, err := scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMediaNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "media.GetByID", err)
+	}
+
+	return media, nil
+}
+
+func (r *MediaRepositoryImpl) GetByIDs(ctx context.Context, ids []int) ([]*Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT ` + mediaColumns + `
+        FROM media m
+        WHERE m.id = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "media.GetByIDs", err)
+	}
+	defer rows.Close()
+
+	media := make([]*Media, 0, len(ids))
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "media.GetByIDs", err)
+		}
+		media = append(media, m)
+	}
+
+	return media, database.LogError(ctx, r.logger, "media.GetByIDs", rows.Err())
+}
+
+func (r *MediaRepositoryImpl) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM media WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "media.Delete", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "media.Delete", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrMediaNotFound
+	}
+
+	return nil
+}
+
+func (r *MediaRepositoryImpl) SetPostMedia(ctx context.Context, postID int, mediaIDs []int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	err := r.setLinks(ctx, "post_media", "post_id", postID, mediaIDs)
+	return database.LogError(ctx, r.logger, "media.SetPostMedia", err)
+}
+
+func (r *MediaRepositoryImpl) ListPostMedia(ctx context.Context, postIDs []int) (map[int][]*Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	media, err := r.listLinks(ctx, "post_media", "post_id", postIDs)
+	return media, database.LogError(ctx, r.logger, "media.ListPostMedia", err)
+}
+
+func (r *MediaRepositoryImpl) ListCarPhotos(ctx context.Context, carIDs []int) (map[int][]*Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	media, err := r.listLinks(ctx, "car_photos", "car_id", carIDs)
+	return media, database.LogError(ctx, r.logger, "media.ListCarPhotos", err)
+}
+
+// setLinks заменяет набор изображений сущности в одной транзакции. table и
+// column задаются только кодом этого пакета.
+func (r *MediaRepositoryImpl) setLinks(ctx context.Context, table, column string, id int, mediaIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, table, column)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+
+	if len(mediaIDs) > 0 {
+		// WITH ORDINALITY нумерует элементы массива, сохраняя порядок
+		query = fmt.Sprintf(`
+        INSERT INTO %s (%s, media_id, position)
+        SELECT $1, media_id, position
+        FROM unnest($2::INTEGER[]) WITH ORDINALITY AS t(media_id, position)`, table, column)
+
+		if _, err := tx.ExecContext(ctx, query, id, pq.Array(mediaIDs)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *MediaRepositoryImpl) listLinks(ctx context.Context, table, column string, ids []int) (map[int][]*Media, error) {
+	result := make(map[int][]*Media, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf(`
+        SELECT l.%s, `+mediaColumns+`
+        FROM %s l
+        JOIN media m ON m.id = l.media_id
+        WHERE l.%s = ANY($1)
+        ORDER BY l.%s, l.position`, column, table, column, column)
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		m, err := scanMedia(prefixScanner{row: rows, prefix: &id})
+		if err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], m)
+	}
+
+	return result, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// prefixScanner читает перед колонками изображения дополнительную колонку
+type prefixScanner struct {
+	row    rowScanner
+	prefix any
+}
+
+func (s prefixScanner) Scan(dest ...any) error {
+	return s.row.Scan(append([]any{s.prefix}, dest...)...)
+}
+
+func scanMedia(row rowScanner) (*Media, error) {
+	media := &Media{}
+	var thumbnails []byte
+	err := row.Scan(
+		&media.ID,
+		&media.OwnerID,
+		&media.StorageKey,
+		&media.ContentType,
+		&media.Size,
+		&media.Width,
+		&media.Height,
+		&media.Checksum,
+		&thumbnails,
+		&media.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(thumbnails, &media.Thumbnails); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
@@ -0,0 +1,364 @@
+// Пакет migrate применяет встроенные SQL-миграции схемы.
+//
+// Текущая версия хранится в таблице schema_migrations в том же формате,
+// что использует golang-migrate, поэтому базы, размеченные внешней
+// утилитой migrate, продолжают работать без изменений.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/lib/pq"
+)
+
+// lockID - ключ advisory lock, под которым выполняются миграции. Несколько
+// реплик, стартующих одновременно, применяют миграции по очереди.
+const lockID int64 = 4827301196
+
+// ErrDirty возвращается, если предыдущая миграция завершилась с ошибкой и
+// схему нужно исправить вручную
+var ErrDirty = errors.New("database schema is dirty")
+
+// undefinedTable - код ошибки Postgres при обращении к несуществующей таблице
+const undefinedTable = "42P01"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration - одна версия схемы из пары файлов NNNNNN_name.up.sql и
+// NNNNNN_name.down.sql
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+	logger     *slog.Logger
+}
+
+// New читает миграции из корня fsys
+func New(db *sql.DB, fsys fs.FS, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+// Migrations возвращает известные миграции по возрастанию версии
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Latest возвращает версию последней известной миграции
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version возвращает текущую версию схемы; 0 означает пустую схему.
+// Не берет блокировку миграций, поэтому подходит для проверок готовности.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	version, dirty, err := readVersion(ctx, m.db)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == undefinedTable {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// Up применяет все еще не примененные миграции
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+
+		// База уже мигрирована более новой версией приложения
+		last := m.Latest()
+		if current >= last {
+			return nil
+		}
+
+		return m.migrate(ctx, conn, current, last)
+	})
+}
+
+// Down откатывает steps последних примененных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+		if current == 0 {
+			return nil
+		}
+
+		index, err := m.index(current)
+		if err != nil {
+			return err
+		}
+
+		var target uint
+		if index-steps >= 0 {
+			target = m.migrations[index-steps].Version
+		}
+
+		return m.migrate(ctx, conn, current, target)
+	})
+}
+
+// Goto приводит схему к версии version, применяя или откатывая миграции.
+// Версия 0 откатывает все миграции.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	if version != 0 {
+		if _, err := m.index(version); err != nil {
+			return err
+		}
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+
+		return m.migrate(ctx, conn, current, version)
+	})
+}
+
+// migrate переводит схему с версии current на версию target. Каждая
+// миграция применяется в отдельной транзакции вместе с записью новой версии.
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, current, target uint) error {
+	if target > current {
+		for _, migration := range m.migrations {
+			if migration.Version <= current || migration.Version > target {
+				continue
+			}
+
+			if err := apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			m.logger.InfoContext(ctx, "applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	}
+
+	if current != 0 {
+		if _, err := m.index(current); err != nil {
+			return err
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current || migration.Version <= target {
+			continue
+		}
+
+		if migration.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		var previous uint
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+
+		if err := apply(ctx, conn, migration.Down, previous); err != nil {
+			return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.logger.InfoContext(ctx, "rolled back migration", "version", migration.Version, "name", migration.Name)
+	}
+
+	return nil
+}
+
+func (m *Migrator) index(version uint) (int, error) {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown migration version %d", version)
+}
+
+// withLock выполняет fn на выделенном соединении под advisory lock.
+// Блокировка снимается вместе с соединением, поэтому при ошибке снятия
+// соединение не возвращается в пул.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+			m.logger.ErrorContext(ctx, "failed to release migration lock", "error", err)
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	query := `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version BIGINT NOT NULL PRIMARY KEY,
+            dirty BOOLEAN NOT NULL
+        )`
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// queryRower позволяет читать версию как через *sql.DB, так и через
+// выделенное соединение
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func readVersion(ctx context.Context, q queryRower) (uint, bool, error) {
+	var (
+		version uint
+		dirty   bool
+	)
+
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	err := q.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// apply выполняет скрипт и записывает новую версию в одной транзакции
+func apply(ctx context.Context, conn *sql.Conn, script string, version uint) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+
+	if version > 0 {
+		query := `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`
+		if _, err := tx.ExecContext(ctx, query, version); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func parse(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d", version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
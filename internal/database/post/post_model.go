@@ -0,0 +1,32 @@
+package post
+
+import "time"
+
+type Post struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	CarID     *int      `db:"car_id"`
+	Body      string    `db:"body"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	// CommentCount - число комментариев вместе с ответами
+	CommentCount int `db:"comment_count"`
+
+	// Данные привязанной машины, заполняются при чтении
+	CarMake  string `db:"car_make"`
+	CarModel string `db:"car_model"`
+}
+
+// Cursor указывает на последний полученный пост при keyset-пагинации
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// ListFilter задает параметры выборки постов
+type ListFilter struct {
+	Make   string
+	Model  string
+	Cursor *Cursor
+	Limit  int
+}
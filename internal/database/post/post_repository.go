@@ -0,0 +1,225 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+// ErrPostNotFound возвращается, если поста с указанным ID нет
+var ErrPostNotFound = apperror.NotFound("post_not_found", "post not found")
+
+type PostRepository interface {
+	Create(ctx context.Context, post *Post) error
+	GetByID(ctx context.Context, id int) (*Post, error)
+	Update(ctx context.Context, post *Post) error
+	Delete(ctx context.Context, id int) error
+	ListByAuthor(ctx context.Context, authorID int, filter ListFilter) ([]*Post, error)
+	ListByFollowedAuthors(ctx context.Context, followerID int, filter ListFilter) ([]*Post, error)
+}
+
+type PostRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewPostRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) PostRepository {
+	return &PostRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+const postSelect = `
+        SELECT p.id, p.user_id, p.car_id, p.body, p.created_at, p.updated_at, p.comment_count,
+               COALESCE(c.make, ''), COALESCE(c.model, '')
+        FROM posts p
+        LEFT JOIN cars c ON c.id = p.car_id`
+
+func (r *PostRepositoryImpl) Create(ctx context.Context, post *Post) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        INSERT INTO posts (user_id, car_id, body, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $4)
+        RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		post.UserID,
+		post.CarID,
+		post.Body,
+		now,
+	).Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt)
+
+	return database.LogError(ctx, r.logger, "post.Create", err)
+}
+
+func (r *PostRepositoryImpl) GetByID(ctx context.Context, id int) (*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := postSelect + `
+        WHERE p.id = $1`
+
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPostNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "post.GetByID", err)
+	}
+
+	return post, nil
+}
+
+func (r *PostRepositoryImpl) Update(ctx context.Context, post *Post) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE posts
+        SET car_id = $1,
+            body = $2,
+            updated_at = $3
+        WHERE id = $4
+        RETURNING created_at, updated_at, comment_count`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		post.CarID,
+		post.Body,
+		now,
+		post.ID,
+	).Scan(&post.CreatedAt, &post.UpdatedAt, &post.CommentCount)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrPostNotFound
+		}
+		return database.LogError(ctx, r.logger, "post.Update", err)
+	}
+
+	return nil
+}
+
+func (r *PostRepositoryImpl) Delete(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM posts WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "post.Delete", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "post.Delete", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrPostNotFound
+	}
+
+	return nil
+}
+
+func (r *PostRepositoryImpl) ListByAuthor(ctx context.Context, authorID int, filter ListFilter) ([]*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	posts, err := r.list(ctx, []string{"p.user_id = $1"}, []any{authorID}, filter)
+	return posts, database.LogError(ctx, r.logger, "post.ListByAuthor", err)
+}
+
+// ListByFollowedAuthors возвращает посты пользователей, на которых подписан
+// followerID, вместе с его собственными постами
+func (r *PostRepositoryImpl) ListByFollowedAuthors(ctx context.Context, followerID int, filter ListFilter) ([]*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cond := `(p.user_id = $1 OR p.user_id IN (SELECT followee_id FROM follows WHERE follower_id = $1))`
+	posts, err := r.list(ctx, []string{cond}, []any{followerID}, filter)
+	return posts, database.LogError(ctx, r.logger, "post.ListByFollowedAuthors", err)
+}
+
+// list выполняет выборку постов в обратном хронологическом порядке
+// с keyset-пагинацией по (created_at, id) и фильтрами по машине
+func (r *PostRepositoryImpl) list(ctx context.Context, conds []string, args []any, filter ListFilter) ([]*Post, error) {
+	if filter.Make != "" {
+		args = append(args, filter.Make)
+		conds = append(conds, fmt.Sprintf("LOWER(c.make) = LOWER($%d)", len(args)))
+	}
+	if filter.Model != "" {
+		args = append(args, filter.Model)
+		conds = append(conds, fmt.Sprintf("LOWER(c.model) = LOWER($%d)", len(args)))
+	}
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.CreatedAt, filter.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(p.created_at, p.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := postSelect
+	if len(conds) > 0 {
+		query += `
+        WHERE ` + strings.Join(conds, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(`
+        ORDER BY p.created_at DESC, p.id DESC
+        LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := make([]*Post, 0)
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPost(row rowScanner) (*Post, error) {
+	post := &Post{}
+	var carID sql.NullInt64
+	err := row.Scan(
+		&post.ID,
+		&post.UserID,
+		&carID,
+		&post.Body,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.CommentCount,
+		&post.CarMake,
+		&post.CarModel,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if carID.Valid {
+		id := int(carID.Int64)
+		post.CarID = &id
+	}
+
+	return post, nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -10,12 +11,13 @@ import (
 
 func NewPostgresDB(cfg *config.Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		cfg.DB.Host,
 		cfg.DB.Username,
 		cfg.DB.Password,
 		cfg.DB.DBName,
 		cfg.DB.Port,
+		cfg.DB.SSLMode,
 	)
 
 	db, err := sql.Open("postgres", dsn)
@@ -24,13 +26,18 @@ func NewPostgresDB(cfg *config.Config) (*sql.DB, error) {
 	}
 
 	// Проверка соединения
-	if err := db.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DB.QueryTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	// Настройка пула соединений
-	db.SetMaxIdleConns(10)
-	db.SetMaxOpenConns(100)
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
 
 	return db, nil
 }
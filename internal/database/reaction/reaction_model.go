@@ -0,0 +1,26 @@
+package reaction
+
+// Виды объектов, на которые можно реагировать
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetCar     = "car"
+)
+
+// Фиксированный набор реакций; совпадает с CHECK в таблицах реакций
+const (
+	Like  = "like"
+	Love  = "love"
+	Fire  = "fire"
+	Wow   = "wow"
+	Laugh = "laugh"
+	Sad   = "sad"
+)
+
+// Summary содержит реакции на объект, агрегированные для показа
+type Summary struct {
+	// Counts - количество реакций каждого вида; виды без реакций отсутствуют
+	Counts map[string]int
+	// Mine - реакция просматривающего пользователя, пустая, если ее нет
+	Mine string
+}
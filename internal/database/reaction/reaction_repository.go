@@ -0,0 +1,178 @@
+package reaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/lib/pq"
+)
+
+// ErrTargetNotFound возвращается, если объекта, на который реагируют, нет
+var ErrTargetNotFound = apperror.NotFound("reaction_target_not_found", "reaction target not found")
+
+// ReactionRepository хранит реакции пользователей. Set и Delete идемпотентны:
+// повторный вызов с теми же аргументами ничего не меняет.
+type ReactionRepository interface {
+	Set(ctx context.Context, target string, targetID, userID int, reaction string) error
+	Delete(ctx context.Context, target string, targetID, userID int) error
+	// Summaries возвращает сводку реакций для каждого из targetIDs одним
+	// запросом; Summary.Mine заполняется для viewerID
+	Summaries(ctx context.Context, target string, targetIDs []int, viewerID int) (map[int]*Summary, error)
+}
+
+type ReactionRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewReactionRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) ReactionRepository {
+	return &ReactionRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+// targetTable описывает таблицу реакций и таблицу объектов одного вида
+type targetTable struct {
+	reactions string
+	column    string
+	objects   string
+}
+
+var targetTables = map[string]targetTable{
+	TargetPost:    {reactions: "post_reactions", column: "post_id", objects: "posts"},
+	TargetComment: {reactions: "comment_reactions", column: "comment_id", objects: "comments"},
+	TargetCar:     {reactions: "car_reactions", column: "car_id", objects: "cars"},
+}
+
+func lookupTarget(target string) (targetTable, error) {
+	t, ok := targetTables[target]
+	if !ok {
+		return targetTable{}, fmt.Errorf("unknown reaction target %q", target)
+	}
+	return t, nil
+}
+
+// Set ставит реакцию или заменяет прежнюю реакцию пользователя на объект
+func (r *ReactionRepositoryImpl) Set(ctx context.Context, target string, targetID, userID int, reaction string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	t, err := lookupTarget(target)
+	if err != nil {
+		return err
+	}
+
+	// Время реакции меняется, только если изменилась сама реакция
+	query := fmt.Sprintf(`
+        INSERT INTO %[1]s (%[2]s, user_id, reaction, created_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (%[2]s, user_id) DO UPDATE
+        SET reaction = EXCLUDED.reaction,
+            created_at = EXCLUDED.created_at
+        WHERE %[1]s.reaction <> EXCLUDED.reaction`, t.reactions, t.column)
+
+	_, err = r.db.ExecContext(ctx, query, targetID, userID, reaction, time.Now())
+	if err != nil {
+		if database.IsForeignKeyViolation(err) {
+			return ErrTargetNotFound
+		}
+		return database.LogError(ctx, r.logger, "reaction.Set", err)
+	}
+
+	return nil
+}
+
+// Delete снимает реакцию пользователя. Отсутствие реакции ошибкой не
+// считается, отсутствие объекта - считается.
+func (r *ReactionRepositoryImpl) Delete(ctx context.Context, target string, targetID, userID int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	t, err := lookupTarget(target)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND user_id = $2`, t.reactions, t.column)
+	result, err := r.db.ExecContext(ctx, query, targetID, userID)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "reaction.Delete", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "reaction.Delete", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	query = fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)`, t.objects)
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, targetID).Scan(&exists); err != nil {
+		return database.LogError(ctx, r.logger, "reaction.Delete", err)
+	}
+
+	if !exists {
+		return ErrTargetNotFound
+	}
+
+	return nil
+}
+
+func (r *ReactionRepositoryImpl) Summaries(ctx context.Context, target string, targetIDs []int, viewerID int) (map[int]*Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	t, err := lookupTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[int]*Summary, len(targetIDs))
+	for _, id := range targetIDs {
+		summaries[id] = &Summary{Counts: map[string]int{}}
+	}
+
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %[2]s, reaction, COUNT(*), BOOL_OR(user_id = $2)
+        FROM %[1]s
+        WHERE %[2]s = ANY($1)
+        GROUP BY %[2]s, reaction`, t.reactions, t.column)
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(targetIDs), viewerID)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "reaction.Summaries", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			targetID int
+			reaction string
+			count    int
+			mine     bool
+		)
+		if err := rows.Scan(&targetID, &reaction, &count, &mine); err != nil {
+			return nil, database.LogError(ctx, r.logger, "reaction.Summaries", err)
+		}
+
+		summary := summaries[targetID]
+		summary.Counts[reaction] = count
+		if mine {
+			summary.Mine = reaction
+		}
+	}
+
+	return summaries, database.LogError(ctx, r.logger, "reaction.Summaries", rows.Err())
+}
@@ -0,0 +1,55 @@
+package search
+
+import "time"
+
+// User - найденный пользователь с данными публичного профиля
+type User struct {
+	ID            int
+	Nickname      *string
+	DisplayName   string
+	AvatarURL     string
+	AvatarMediaID *int
+	Rank          float64
+}
+
+// Car - найденная машина
+type Car struct {
+	ID         int
+	UserID     int
+	Make       string
+	Model      string
+	Generation string
+	Year       int
+	Rank       float64
+}
+
+// Post - найденный пост
+type Post struct {
+	ID        int
+	UserID    int
+	Body      string
+	CreatedAt time.Time
+	Rank      float64
+}
+
+// Cursor указывает на последний полученный результат при keyset-пагинации
+// по (rank, id)
+type Cursor struct {
+	Rank float64
+	ID   int
+}
+
+// Query задает параметры поиска. Text - запрос в свободной форме:
+// поддерживаются кавычки для фраз, OR и минус для исключения слов.
+type Query struct {
+	Text string
+
+	// Фильтры машин
+	Make     string
+	Model    string
+	YearFrom int
+	YearTo   int
+
+	Cursor *Cursor
+	Limit  int
+}
@@ -0,0 +1,227 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+// SearchRepository выполняет полнотекстовый поиск. Результаты упорядочены
+// по убыванию релевантности (rank), при равной релевантности - по убыванию id.
+type SearchRepository interface {
+	Users(ctx context.Context, query Query) ([]*User, error)
+	Cars(ctx context.Context, query Query) ([]*Car, error)
+	Posts(ctx context.Context, query Query) ([]*Post, error)
+}
+
+type SearchRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewSearchRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) SearchRepository {
+	return &SearchRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+// tsQuery разбирает запрос русской и английской конфигурациями и объединяет
+// результаты через OR, как и поисковые векторы в таблицах
+const tsQuery = `(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))`
+
+// carTsQuery дополнительно разбирает запрос конфигурацией simple: марка,
+// модель, поколение и двигатель хранятся в векторе без стемминга, а русская
+// и английская конфигурации превращают, например, Mercedes в merced
+const carTsQuery = `(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) || websearch_to_tsquery('simple', $1))`
+
+// Users ищет по имени, городу и описанию профиля и по началу никнейма.
+// Совпадение никнейма поднимает пользователя выше совпадений по тексту.
+func (r *SearchRepositoryImpl) Users(ctx context.Context, query Query) ([]*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	inner := `
+        SELECT id, nickname, display_name, avatar_url, avatar_media_id,
+               (CASE
+                    WHEN LOWER(nickname) = LOWER($1) THEN 2
+                    WHEN LOWER(nickname) LIKE $2 THEN 1
+                    ELSE 0
+                END + ts_rank(search_vector, ` + tsQuery + `))::FLOAT8 AS rank
+        FROM users
+        WHERE LOWER(nickname) LIKE $2 OR search_vector @@ ` + tsQuery
+
+	args := []any{query.Text, likePrefix(query.Text)}
+
+	rows, err := r.ranked(ctx, inner, args, query)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "search.Users", err)
+	}
+	defer rows.Close()
+
+	users := make([]*User, 0)
+	for rows.Next() {
+		user := &User{}
+		var nickname sql.NullString
+		var avatarMediaID sql.NullInt64
+		err := rows.Scan(
+			&user.ID,
+			&nickname,
+			&user.DisplayName,
+			&user.AvatarURL,
+			&avatarMediaID,
+			&user.Rank,
+		)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "search.Users", err)
+		}
+
+		if nickname.Valid {
+			user.Nickname = &nickname.String
+		}
+		if avatarMediaID.Valid {
+			id := int(avatarMediaID.Int64)
+			user.AvatarMediaID = &id
+		}
+
+		users = append(users, user)
+	}
+
+	return users, database.LogError(ctx, r.logger, "search.Users", rows.Err())
+}
+
+// Cars ищет по марке, модели, поколению, двигателю и цвету. Без текста
+// запроса машины только фильтруются, и у всех результатов rank равен нулю.
+func (r *SearchRepositoryImpl) Cars(ctx context.Context, query Query) ([]*Car, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var conds []string
+	var args []any
+	rank := `0::FLOAT8`
+
+	if query.Text != "" {
+		args = append(args, query.Text)
+		conds = append(conds, `search_vector @@ `+carTsQuery)
+		rank = `ts_rank(search_vector, ` + carTsQuery + `)::FLOAT8`
+	}
+	if query.Make != "" {
+		args = append(args, query.Make)
+		conds = append(conds, fmt.Sprintf("LOWER(make) = LOWER($%d)", len(args)))
+	}
+	if query.Model != "" {
+		args = append(args, query.Model)
+		conds = append(conds, fmt.Sprintf("LOWER(model) = LOWER($%d)", len(args)))
+	}
+	if query.YearFrom > 0 {
+		args = append(args, query.YearFrom)
+		conds = append(conds, fmt.Sprintf("year >= $%d", len(args)))
+	}
+	if query.YearTo > 0 {
+		args = append(args, query.YearTo)
+		conds = append(conds, fmt.Sprintf("year <= $%d", len(args)))
+	}
+
+	inner := `
+        SELECT id, user_id, make, model, generation, year, ` + rank + ` AS rank
+        FROM cars`
+	if len(conds) > 0 {
+		inner += `
+        WHERE ` + strings.Join(conds, " AND ")
+	}
+
+	rows, err := r.ranked(ctx, inner, args, query)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "search.Cars", err)
+	}
+	defer rows.Close()
+
+	cars := make([]*Car, 0)
+	for rows.Next() {
+		car := &Car{}
+		err := rows.Scan(
+			&car.ID,
+			&car.UserID,
+			&car.Make,
+			&car.Model,
+			&car.Generation,
+			&car.Year,
+			&car.Rank,
+		)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "search.Cars", err)
+		}
+		cars = append(cars, car)
+	}
+
+	return cars, database.LogError(ctx, r.logger, "search.Cars", rows.Err())
+}
+
+// Posts ищет по тексту постов
+func (r *SearchRepositoryImpl) Posts(ctx context.Context, query Query) ([]*Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	inner := `
+        SELECT id, user_id, body, created_at,
+               ts_rank(search_vector, ` + tsQuery + `)::FLOAT8 AS rank
+        FROM posts
+        WHERE search_vector @@ ` + tsQuery
+
+	rows, err := r.ranked(ctx, inner, []any{query.Text}, query)
+	if err != nil {
+		return nil, database.LogError(ctx, r.logger, "search.Posts", err)
+	}
+	defer rows.Close()
+
+	posts := make([]*Post, 0)
+	for rows.Next() {
+		post := &Post{}
+		err := rows.Scan(
+			&post.ID,
+			&post.UserID,
+			&post.Body,
+			&post.CreatedAt,
+			&post.Rank,
+		)
+		if err != nil {
+			return nil, database.LogError(ctx, r.logger, "search.Posts", err)
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, database.LogError(ctx, r.logger, "search.Posts", rows.Err())
+}
+
+// ranked оборачивает выборку с колонками id и rank в keyset-пагинацию
+// по (rank, id). rank вычисляется заново при каждом запросе одинаково,
+// поэтому курсор из предыдущей страницы остается точным.
+func (r *SearchRepositoryImpl) ranked(ctx context.Context, inner string, args []any, query Query) (*sql.Rows, error) {
+	sqlQuery := `
+        SELECT *
+        FROM (` + inner + `
+        ) AS results`
+
+	if query.Cursor != nil {
+		args = append(args, query.Cursor.Rank, query.Cursor.ID)
+		sqlQuery += fmt.Sprintf(`
+        WHERE (rank, id) < ($%d, $%d)`, len(args)-1, len(args))
+	}
+
+	args = append(args, query.Limit)
+	sqlQuery += fmt.Sprintf(`
+        ORDER BY rank DESC, id DESC
+        LIMIT $%d`, len(args))
+
+	return r.db.QueryContext(ctx, sqlQuery, args...)
+}
+
+// likePrefix строит шаблон LIKE для поиска по началу строки, экранируя
+// служебные символы запроса
+func likePrefix(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(strings.ToLower(s)) + "%"
+}
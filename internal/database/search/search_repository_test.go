@@ -0,0 +1,138 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/database/migrate"
+	"github.com/NikitaBelov-mobile/car-social/migrations"
+	_ "github.com/lib/pq"
+)
+
+// testDB создает отдельную схему в базе из TEST_DATABASE_URL, применяет в ней
+// миграции и удаляет ее после теста. Без TEST_DATABASE_URL тест пропускается.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("search_test_%d", time.Now().UnixNano())
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrate.New(db, migrations.FS, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	return db
+}
+
+// withSearchPath добавляет к строке подключения схему по умолчанию
+func withSearchPath(dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func TestCarsMatchesUnstemmedMakeAndModel(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRowContext(ctx, `INSERT INTO users (phone, password_hash) VALUES ('+79990000001', 'x') RETURNING id`).Scan(&userID)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+
+	cars := map[string]int{}
+	for _, car := range []struct{ make, model, engine string }{
+		{"Mercedes", "E-Class", "M274"},
+		{"Toyota", "Camry", "2AR-FE"},
+	} {
+		var id int
+		err := db.QueryRowContext(ctx, `
+        INSERT INTO cars (user_id, make, model, engine, year)
+        VALUES ($1, $2, $3, $4, 2018)
+        RETURNING id`, userID, car.make, car.model, car.engine).Scan(&id)
+		if err != nil {
+			t.Fatalf("insert car: %v", err)
+		}
+		cars[car.make] = id
+	}
+
+	repo := NewSearchRepositoryImpl(db, 5*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Русская и английская конфигурации стеммят эти слова (merced, camri),
+	// а в векторе они хранятся без изменений
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "Mercedes", want: "Mercedes"},
+		{query: "mercedes e-class", want: "Mercedes"},
+		{query: "Camry", want: "Toyota"},
+		{query: "Toyota Camry", want: "Toyota"},
+		{query: "2AR-FE", want: "Toyota"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			found, err := repo.Cars(ctx, Query{Text: tt.query, Limit: 10})
+			if err != nil {
+				t.Fatalf("Cars() error = %v", err)
+			}
+
+			if len(found) != 1 || found[0].ID != cars[tt.want] {
+				ids := make([]int, 0, len(found))
+				for _, car := range found {
+					ids = append(ids, car.ID)
+				}
+				t.Errorf("Cars(%q) = %v, want [%d]", tt.query, ids, cars[tt.want])
+			}
+		})
+	}
+}
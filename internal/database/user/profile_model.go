@@ -0,0 +1,25 @@
+package user
+
+import "time"
+
+// Видимость профиля для других пользователей
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// Profile - публичные данные пользователя. Никнейм необязателен, но если
+// задан, уникален без учета регистра.
+type Profile struct {
+	UserID      int     `db:"id"`
+	Nickname    *string `db:"nickname"`
+	DisplayName string  `db:"display_name"`
+	Bio         string  `db:"bio"`
+	City        string  `db:"city"`
+	AvatarURL   string  `db:"avatar_url"`
+	// AvatarMediaID - аватар, загруженный через media; имеет приоритет над AvatarURL
+	AvatarMediaID *int      `db:"avatar_media_id"`
+	Visibility    string    `db:"profile_visibility"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
@@ -0,0 +1,128 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+var ErrNicknameTaken = apperror.Conflict("nickname_taken", "nickname already taken")
+
+type ProfileRepository interface {
+	GetProfile(ctx context.Context, userID int) (*Profile, error)
+	UpdateProfile(ctx context.Context, profile *Profile) error
+}
+
+type ProfileRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewProfileRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) ProfileRepository {
+	return &ProfileRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+func (r *ProfileRepositoryImpl) GetProfile(ctx context.Context, userID int) (*Profile, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        SELECT id, nickname, display_name, bio, city, avatar_url, avatar_media_id, profile_visibility, created_at, updated_at
+        FROM users
+        WHERE id = $1`
+
+	profile, err := scanProfile(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "user.GetProfile", err)
+	}
+
+	return profile, nil
+}
+
+func (r *ProfileRepositoryImpl) UpdateProfile(ctx context.Context, profile *Profile) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE users
+        SET nickname = $1,
+            display_name = $2,
+            bio = $3,
+            city = $4,
+            avatar_url = $5,
+            avatar_media_id = $6,
+            profile_visibility = $7,
+            updated_at = $8
+        WHERE id = $9
+        RETURNING created_at, updated_at`
+
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query,
+		profile.Nickname,
+		profile.DisplayName,
+		profile.Bio,
+		profile.City,
+		profile.AvatarURL,
+		profile.AvatarMediaID,
+		profile.Visibility,
+		now,
+		profile.UserID,
+	).Scan(&profile.CreatedAt, &profile.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		if database.IsUniqueViolation(err) {
+			return ErrNicknameTaken
+		}
+		return database.LogError(ctx, r.logger, "user.UpdateProfile", err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProfile(row rowScanner) (*Profile, error) {
+	profile := &Profile{}
+	var (
+		nickname      sql.NullString
+		avatarMediaID sql.NullInt64
+	)
+	err := row.Scan(
+		&profile.UserID,
+		&nickname,
+		&profile.DisplayName,
+		&profile.Bio,
+		&profile.City,
+		&profile.AvatarURL,
+		&avatarMediaID,
+		&profile.Visibility,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if nickname.Valid {
+		profile.Nickname = &nickname.String
+	}
+	if avatarMediaID.Valid {
+		id := int(avatarMediaID.Int64)
+		profile.AvatarMediaID = &id
+	}
+
+	return profile, nil
+}
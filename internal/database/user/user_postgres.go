@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"database/sql"
 	"time"
 )
@@ -13,14 +14,14 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
 
-func (r *PostgresRepository) Create(user *User) error {
+func (r *PostgresRepository) Create(ctx context.Context, user *User) error {
 	query := `
         INSERT INTO users (phone, password_hash, created_at, updated_at)
         VALUES ($1, $2, $3, $3)
         RETURNING id`
 
 	now := time.Now()
-	return r.db.QueryRow(query,
+	return r.db.QueryRowContext(ctx, query,
 		user.Phone,
 		user.PasswordHash,
 		now,
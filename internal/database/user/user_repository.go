@@ -1,119 +1,214 @@
 package user
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"log/slog"
 	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+var (
+	ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+	ErrUserExists   = apperror.Conflict("user_already_exists", "user already exists")
+	ErrPhoneTaken   = apperror.Conflict("phone_taken", "phone number already taken")
 )
 
 type UserRepository interface {
-	Create(user *User) error
-	GetByPhone(phone string) (*User, error)
-	GetByID(id int) (*User, error)
-	Update(user *User) error
+	Create(ctx context.Context, user *User) error
+	GetByPhone(ctx context.Context, phone string) (*User, error)
+	GetByID(ctx context.Context, id int) (*User, error)
+	Update(ctx context.Context, user *User) error
+	MarkPhoneVerified(ctx context.Context, id int, verifiedAt time.Time) error
+	ReplacePasswordHash(ctx context.Context, id int, oldHash, newHash string) error
 }
 
 type UserRepositoryImpl struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
 }
 
-func NewUserRepositoryImpl(db *sql.DB) UserRepository {
-	return &UserRepositoryImpl{db: db}
+func NewUserRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) UserRepository {
+	return &UserRepositoryImpl{db: db, timeout: timeout, logger: logger}
 }
 
-func (r *UserRepositoryImpl) Create(user *User) error {
+func (r *UserRepositoryImpl) Create(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
 	query := `
         INSERT INTO users (phone, password_hash, created_at, updated_at)
         VALUES ($1, $2, $3, $3)
         RETURNING id, created_at, updated_at`
 
 	now := time.Now()
-	err := r.db.QueryRow(query,
+	err := r.db.QueryRowContext(ctx, query,
 		user.Phone,
 		user.PasswordHash,
 		now,
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
-		return err
+		if database.IsUniqueViolation(err) {
+			return ErrUserExists
+		}
+		return database.LogError(ctx, r.logger, "user.Create", err)
 	}
 
 	return nil
 }
 
-func (r *UserRepositoryImpl) GetByPhone(phone string) (*User, error) {
+func (r *UserRepositoryImpl) GetByPhone(ctx context.Context, phone string) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
 	user := &User{}
 	query := `
-        SELECT id, phone, password_hash, created_at, updated_at
+        SELECT id, phone, password_hash, phone_verified_at, created_at, updated_at
         FROM users
         WHERE phone = $1`
 
-	err := r.db.QueryRow(query, phone).Scan(
+	var phoneVerifiedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, phone).Scan(
 		&user.ID,
 		&user.Phone,
 		&user.PasswordHash,
+		&phoneVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, ErrUserNotFound
 		}
-		return nil, err
+		return nil, database.LogError(ctx, r.logger, "user.GetByPhone", err)
+	}
+
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
 	}
 
 	return user, nil
 }
 
-func (r *UserRepositoryImpl) GetByID(id int) (*User, error) {
+func (r *UserRepositoryImpl) GetByID(ctx context.Context, id int) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
 	user := &User{}
 	query := `
-        SELECT id, phone, password_hash, created_at, updated_at
+        SELECT id, phone, password_hash, phone_verified_at, created_at, updated_at
         FROM users
         WHERE id = $1`
 
-	err := r.db.QueryRow(query, id).Scan(
+	var phoneVerifiedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Phone,
 		&user.PasswordHash,
+		&phoneVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
-		return nil, err
+		return nil, database.LogError(ctx, r.logger, "user.GetByID", err)
+	}
+
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
 	}
 
 	return user, nil
 }
 
-func (r *UserRepositoryImpl) Update(user *User) error {
+func (r *UserRepositoryImpl) Update(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// Смена номера сбрасывает его подтверждение
 	query := `
         UPDATE users
         SET phone = $1,
             password_hash = $2,
+            phone_verified_at = CASE WHEN phone = $1 THEN phone_verified_at END,
             updated_at = $3
         WHERE id = $4
-        RETURNING created_at, updated_at`
+        RETURNING phone_verified_at, created_at, updated_at`
 
 	now := time.Now()
-	err := r.db.QueryRow(query,
+	var phoneVerifiedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query,
 		user.Phone,
 		user.PasswordHash,
 		now,
 		user.ID,
-	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	).Scan(&phoneVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
-		return err
+		if database.IsUniqueViolation(err) {
+			return ErrPhoneTaken
+		}
+		return database.LogError(ctx, r.logger, "user.Update", err)
+	}
+
+	user.PhoneVerifiedAt = nil
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) MarkPhoneVerified(ctx context.Context, id int, verifiedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE users
+        SET phone_verified_at = $1,
+            updated_at = $1
+        WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, verifiedAt, id)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "user.MarkPhoneVerified", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "user.MarkPhoneVerified", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
 	}
 
 	return nil
 }
+
+// ReplacePasswordHash заменяет хеш пароля, только если он не изменился с
+// момента чтения: пересчет хеша при входе не должен затереть пароль,
+// одновременно измененный пользователем
+func (r *UserRepositoryImpl) ReplacePasswordHash(ctx context.Context, id int, oldHash, newHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE users
+        SET password_hash = $1
+        WHERE id = $2 AND password_hash = $3`
+
+	_, err := r.db.ExecContext(ctx, query, newHash, id, oldHash)
+	return database.LogError(ctx, r.logger, "user.ReplacePasswordHash", err)
+}
@@ -0,0 +1,30 @@
+package verification
+
+import "time"
+
+// Назначения одноразовых кодов
+const (
+	PurposePhoneVerification = "phone_verification"
+	PurposePasswordReset     = "password_reset"
+)
+
+// Limits ограничивает частоту отправки кодов на один номер с одним назначением
+type Limits struct {
+	// ResendInterval - минимальный интервал между кодами
+	ResendInterval time.Duration
+	// MaxPerHour - максимум кодов за последний час
+	MaxPerHour int
+}
+
+// Code описывает отправленный по SMS одноразовый код. Сам код не хранится,
+// только его хеш.
+type Code struct {
+	ID         int        `db:"id"`
+	Phone      string     `db:"phone"`
+	Purpose    string     `db:"purpose"`
+	CodeHash   string     `db:"code_hash"`
+	Attempts   int        `db:"attempts"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
@@ -0,0 +1,208 @@
+package verification
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+var (
+	ErrCodeNotFound      = apperror.NotFound("code_not_found", "code not found")
+	ErrCodeConsumed      = apperror.Conflict("code_consumed", "code already consumed")
+	ErrAttemptsExhausted = apperror.TooManyRequests("attempts_exhausted", "no attempts left")
+	ErrCodeLimitExceeded = apperror.TooManyRequests("code_limit_exceeded", "code limit exceeded")
+)
+
+type VerificationRepository interface {
+	CreateCode(ctx context.Context, code *Code, limits Limits) error
+	GetLatestCode(ctx context.Context, phone, purpose string) (*Code, error)
+	ClaimAttempt(ctx context.Context, id, maxAttempts int) error
+	ConsumeCode(ctx context.Context, id int) error
+	DeleteExpiredCodes(ctx context.Context, before time.Time) (int64, error)
+}
+
+type VerificationRepositoryImpl struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewVerificationRepositoryImpl(db *sql.DB, timeout time.Duration, logger *slog.Logger) VerificationRepository {
+	return &VerificationRepositoryImpl{db: db, timeout: timeout, logger: logger}
+}
+
+// CreateCode сохраняет код, если для его номера и назначения не превышены
+// limits, иначе возвращает ErrCodeLimitExceeded. Проверка и вставка
+// выполняются под advisory-блокировкой номера, поэтому параллельные запросы
+// не могут вместе превысить лимиты.
+func (r *VerificationRepositoryImpl) CreateCode(ctx context.Context, code *Code, limits Limits) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.CreateCode", err)
+	}
+	defer tx.Rollback()
+
+	// Блокировка снимается вместе с завершением транзакции
+	lockKey := "verification_codes:" + code.Purpose + ":" + code.Phone
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey); err != nil {
+		return database.LogError(ctx, r.logger, "verification.CreateCode", err)
+	}
+
+	now := time.Now()
+
+	query := `
+        SELECT COUNT(*) FILTER (WHERE created_at >= $3), MAX(created_at)
+        FROM verification_codes
+        WHERE phone = $1 AND purpose = $2`
+
+	var count int
+	var lastCreatedAt sql.NullTime
+	err = tx.QueryRowContext(ctx, query, code.Phone, code.Purpose, now.Add(-time.Hour)).Scan(&count, &lastCreatedAt)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.CreateCode", err)
+	}
+
+	if count >= limits.MaxPerHour {
+		return ErrCodeLimitExceeded
+	}
+	if lastCreatedAt.Valid && now.Sub(lastCreatedAt.Time) < limits.ResendInterval {
+		return ErrCodeLimitExceeded
+	}
+
+	query = `
+        INSERT INTO verification_codes (phone, purpose, code_hash, expires_at, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at`
+
+	err = tx.QueryRowContext(ctx, query,
+		code.Phone,
+		code.Purpose,
+		code.CodeHash,
+		code.ExpiresAt,
+		now,
+	).Scan(&code.ID, &code.CreatedAt)
+
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.CreateCode", err)
+	}
+
+	return database.LogError(ctx, r.logger, "verification.CreateCode", tx.Commit())
+}
+
+// GetLatestCode возвращает последний отправленный код; действителен
+// только он, более ранние коды заменяются новыми
+func (r *VerificationRepositoryImpl) GetLatestCode(ctx context.Context, phone, purpose string) (*Code, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	code := &Code{}
+	query := `
+        SELECT id, phone, purpose, code_hash, attempts, expires_at, consumed_at, created_at
+        FROM verification_codes
+        WHERE phone = $1 AND purpose = $2
+        ORDER BY created_at DESC, id DESC
+        LIMIT 1`
+
+	var consumedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, phone, purpose).Scan(
+		&code.ID,
+		&code.Phone,
+		&code.Purpose,
+		&code.CodeHash,
+		&code.Attempts,
+		&code.ExpiresAt,
+		&consumedAt,
+		&code.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCodeNotFound
+		}
+		return nil, database.LogError(ctx, r.logger, "verification.GetLatestCode", err)
+	}
+
+	if consumedAt.Valid {
+		code.ConsumedAt = &consumedAt.Time
+	}
+
+	return code, nil
+}
+
+// ClaimAttempt засчитывает попытку ввода кода до его проверки. Проверка
+// лимита и увеличение счетчика выполняются одним запросом, поэтому
+// параллельные запросы не могут сделать больше maxAttempts попыток.
+// Возвращает ErrAttemptsExhausted, если попыток не осталось или код уже
+// использован.
+func (r *VerificationRepositoryImpl) ClaimAttempt(ctx context.Context, id, maxAttempts int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `
+        UPDATE verification_codes
+        SET attempts = attempts + 1
+        WHERE id = $1 AND attempts < $2 AND consumed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, maxAttempts)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.ClaimAttempt", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.ClaimAttempt", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrAttemptsExhausted
+	}
+
+	return nil
+}
+
+// ConsumeCode помечает код использованным. Если код уже был использован
+// параллельным запросом, возвращает ошибку.
+func (r *VerificationRepositoryImpl) ConsumeCode(ctx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE verification_codes SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.ConsumeCode", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return database.LogError(ctx, r.logger, "verification.ConsumeCode", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCodeConsumed
+	}
+
+	return nil
+}
+
+// DeleteExpiredCodes удаляет коды, истекшие до before, и возвращает их
+// количество
+func (r *VerificationRepositoryImpl) DeleteExpiredCodes(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM verification_codes WHERE expires_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, database.LogError(ctx, r.logger, "verification.DeleteExpiredCodes", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	return deleted, database.LogError(ctx, r.logger, "verification.DeleteExpiredCodes", err)
+}
@@ -0,0 +1,158 @@
+// Пакет metrics описывает метрики сервиса в формате Prometheus.
+//
+// Все метрики регистрируются в собственном реестре, а не в глобальном
+// prometheus.DefaultRegisterer, поэтому реестр можно создать в тесте и
+// прочитать через Handler без запуска сервера.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "car_social"
+
+// NewRegistry создает реестр со стандартными метриками Go-рантайма и процесса
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler отдает метрики реестра в текстовом формате Prometheus
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterDBStats публикует статистику пула соединений db (sql.DB.Stats)
+// с меткой db_name
+func RegisterDBStats(registry prometheus.Registerer, db *sql.DB, dbName string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// HTTP - метрики обработки HTTP-запросов
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+func NewHTTP(registry prometheus.Registerer) *HTTP {
+	m := &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route template, method and status.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route template and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}),
+	}
+
+	registry.MustRegister(m.requests, m.duration, m.inFlight)
+	return m
+}
+
+// Started отмечает начало обработки запроса
+func (m *HTTP) Started() {
+	m.inFlight.Inc()
+}
+
+// Finished учитывает обработанный запрос. route - шаблон маршрута gin
+// (например, /users/:id), а не фактический путь, чтобы число рядов метрики
+// не зависело от ID в пути.
+func (m *HTTP) Finished(route, method string, status int, elapsed time.Duration) {
+	m.inFlight.Dec()
+	m.requests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+}
+
+// Auth - счетчики событий аутентификации
+type Auth struct {
+	signUps          prometheus.Counter
+	signInFailures   *prometheus.CounterVec
+	refreshRotations prometheus.Counter
+	refreshReuses    prometheus.Counter
+	logouts          *prometheus.CounterVec
+}
+
+func NewAuth(registry prometheus.Registerer) *Auth {
+	m := &Auth{
+		signUps: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "sign_ups_total",
+			Help:      "Number of registered users.",
+		}),
+		signInFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "sign_in_failures_total",
+			Help:      "Number of rejected sign-in attempts by reason.",
+		}, []string{"reason"}),
+		refreshRotations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "refresh_rotations_total",
+			Help:      "Number of refresh tokens exchanged for a new pair.",
+		}),
+		refreshReuses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "refresh_token_reuses_total",
+			Help:      "Number of detected refresh token reuses.",
+		}),
+		logouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "logouts_total",
+			Help:      "Number of logouts by scope: session, device or all.",
+		}, []string{"scope"}),
+	}
+
+	registry.MustRegister(m.signUps, m.signInFailures, m.refreshRotations, m.refreshReuses, m.logouts)
+	return m
+}
+
+func (m *Auth) SignUp() {
+	m.signUps.Inc()
+}
+
+// SignInFailed учитывает отклоненный вход; reason - код ошибки ответа
+func (m *Auth) SignInFailed(reason string) {
+	m.signInFailures.WithLabelValues(reason).Inc()
+}
+
+func (m *Auth) RefreshRotated() {
+	m.refreshRotations.Inc()
+}
+
+func (m *Auth) RefreshReused() {
+	m.refreshReuses.Inc()
+}
+
+// Logout учитывает выход; scope - session (по refresh token), device
+// (завершение сессии устройства) или all (со всех устройств)
+func (m *Auth) Logout(scope string) {
+	m.logouts.WithLabelValues(scope).Inc()
+}
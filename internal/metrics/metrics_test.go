@@ -0,0 +1,102 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/metrics"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHTTPMetricsUseRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := metrics.NewRegistry()
+	router := gin.New()
+	router.Use(middleware.Metrics(metrics.NewHTTP(registry)))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, registry)
+
+	for _, want := range []string{
+		`car_social_http_requests_total{method="GET",route="/users/:id",status="200"} 2`,
+		`car_social_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`car_social_http_request_duration_seconds_count{method="GET",route="/users/:id"} 2`,
+		`car_social_http_requests_in_flight 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output does not contain %q", want)
+		}
+	}
+
+	// Фактический путь с ID не должен попадать в метки
+	if strings.Contains(body, `route="/users/1"`) {
+		t.Error("metrics output contains raw request path")
+	}
+}
+
+func TestAuthCounters(t *testing.T) {
+	registry := metrics.NewRegistry()
+	auth := metrics.NewAuth(registry)
+
+	auth.SignUp()
+	auth.SignInFailed("invalid_credentials")
+	auth.SignInFailed("invalid_credentials")
+	auth.SignInFailed("sign_in_locked")
+	auth.RefreshRotated()
+	auth.RefreshReused()
+	auth.Logout("session")
+	auth.Logout("all")
+
+	body := scrape(t, registry)
+
+	for _, want := range []string{
+		`car_social_auth_sign_ups_total 1`,
+		`car_social_auth_sign_in_failures_total{reason="invalid_credentials"} 2`,
+		`car_social_auth_sign_in_failures_total{reason="sign_in_locked"} 1`,
+		`car_social_auth_refresh_rotations_total 1`,
+		`car_social_auth_refresh_token_reuses_total 1`,
+		`car_social_auth_logouts_total{scope="session"} 1`,
+		`car_social_auth_logouts_total{scope="all"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output does not contain %q", want)
+		}
+	}
+}
+
+// scrape читает метрики реестра так же, как их читает Prometheus
+func scrape(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+
+	server := httptest.NewServer(metrics.Handler(registry))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("scrape metrics: status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+
+	return string(body)
+}
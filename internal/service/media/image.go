@@ -0,0 +1,272 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"sort"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Форматы, которые принимаются на загрузку: тип по содержимому файла и имя
+// формата в пакете image
+var allowedTypes = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// encodedImage - изображение, готовое к сохранению
+type encodedImage struct {
+	data        []byte
+	contentType string
+	ext         string
+	width       int
+	height      int
+}
+
+// processor проверяет и перекодирует загруженные изображения.
+//
+// Исходный файл никогда не сохраняется как есть: изображение декодируется
+// и кодируется заново, поэтому EXIF и другие метаданные (геопозиция,
+// модель камеры) отбрасываются. Ориентация из EXIF применяется к пикселям
+// до перекодирования, чтобы снимки с телефона не оказались повернутыми.
+type processor struct {
+	maxPixels      int
+	jpegQuality    int
+	thumbnailSizes []int
+}
+
+func newProcessor(maxPixels, jpegQuality int, thumbnailSizes []int) *processor {
+	sizes := append([]int(nil), thumbnailSizes...)
+	sort.Ints(sizes)
+
+	return &processor{
+		maxPixels:      maxPixels,
+		jpegQuality:    jpegQuality,
+		thumbnailSizes: sizes,
+	}
+}
+
+// process возвращает очищенное изображение и миниатюры. Миниатюры строятся
+// только для размеров меньше исходного изображения.
+func (p *processor) process(data []byte) (*encodedImage, map[int]*encodedImage, error) {
+	format, ok := allowedTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, nil, ErrUnsupportedType
+	}
+
+	// Размеры проверяются до декодирования: заголовок маленького файла может
+	// описывать изображение на десятки гигабайт
+	cfg, decodedFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decodedFormat != format {
+		return nil, nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > p.maxPixels {
+		return nil, nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, ErrInvalidImage
+	}
+
+	if format == "jpeg" {
+		img = applyOrientation(img, exifOrientation(data))
+	}
+
+	// PNG сохраняем в PNG, чтобы не потерять прозрачность, остальное в JPEG
+	keepPNG := format == "png"
+
+	original, err := p.encode(img, keepPNG)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thumbnails := make(map[int]*encodedImage)
+	bounds := img.Bounds()
+	for _, size := range p.thumbnailSizes {
+		if size >= max(bounds.Dx(), bounds.Dy()) {
+			break
+		}
+
+		thumbnail, err := p.encode(resize(img, size), keepPNG)
+		if err != nil {
+			return nil, nil, err
+		}
+		thumbnails[size] = thumbnail
+	}
+
+	return original, thumbnails, nil
+}
+
+func (p *processor) encode(img image.Image, keepPNG bool) (*encodedImage, error) {
+	bounds := img.Bounds()
+	encoded := &encodedImage{width: bounds.Dx(), height: bounds.Dy()}
+
+	var buf bytes.Buffer
+	if keepPNG {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		encoded.contentType, encoded.ext = "image/png", "png"
+	} else {
+		// В JPEG нет прозрачности: полупрозрачные пиксели накладываем на
+		// белый фон, иначе они станут черными
+		flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: p.jpegQuality}); err != nil {
+			return nil, err
+		}
+		encoded.contentType, encoded.ext = "image/jpeg", "jpg"
+	}
+
+	encoded.data = buf.Bytes()
+	return encoded, nil
+}
+
+// resize уменьшает изображение так, чтобы длинная сторона была равна size
+func resize(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := size, size
+	if bounds.Dx() > bounds.Dy() {
+		height = max(1, bounds.Dy()*size/bounds.Dx())
+	} else {
+		width = max(1, bounds.Dx()*size/bounds.Dy())
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// applyOrientation поворачивает и отражает изображение согласно тегу
+// Orientation из EXIF (значения 1-8)
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	// Значения 5-8 меняют ширину и высоту местами
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				sx, sy = w-1-x, y
+			case 3: // поворот на 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				sx, sy = x, h-1-y
+			case 5: // отражение относительно главной диагонали
+				sx, sy = y, x
+			case 6: // поворот на 90° по часовой стрелке
+				sx, sy = y, h-1-x
+			case 7: // отражение относительно побочной диагонали
+				sx, sy = w-1-y, h-1-x
+			case 8: // поворот на 90° против часовой стрелки
+				sx, sy = w-1-y, x
+			}
+
+			si := src.PixOffset(sx, sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
+
+// exifOrientation читает тег Orientation из сегмента APP1 файла JPEG.
+// Возвращает 1 (без преобразований), если тега нет или EXIF поврежден.
+func exifOrientation(data []byte) int {
+	const (
+		markerSOI  = 0xD8
+		markerAPP1 = 0xE1
+		markerSOS  = 0xDA
+		tagOrient  = 0x0112
+	)
+
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == markerSOS {
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		pos += 2 + length
+
+		if marker != markerAPP1 || !bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			continue
+		}
+
+		tiff := segment[6:]
+		if len(tiff) < 8 {
+			return 1
+		}
+
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 1
+		}
+
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd < 8 || ifd+2 > len(tiff) {
+			return 1
+		}
+
+		entries := int(order.Uint16(tiff[ifd:]))
+		for i := 0; i < entries; i++ {
+			entry := ifd + 2 + i*12
+			if entry+12 > len(tiff) {
+				return 1
+			}
+			if order.Uint16(tiff[entry:]) == tagOrient {
+				return int(order.Uint16(tiff[entry+8:]))
+			}
+		}
+
+		return 1
+	}
+
+	return 1
+}
@@ -0,0 +1,189 @@
+// Пакет media принимает загружаемые изображения: проверяет и очищает их,
+// строит миниатюры и сохраняет файлы в MediaStore, а метаданные в таблицу
+// media.
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+)
+
+var (
+	ErrFileTooLarge    = apperror.TooLarge("file_too_large", "file is too large")
+	ErrImageTooLarge   = apperror.TooLarge("image_too_large", "image dimensions are too large")
+	ErrUnsupportedType = apperror.UnsupportedType("unsupported_media_type", "only JPEG, PNG and WebP images are supported")
+	ErrInvalidImage    = apperror.InvalidInput("invalid_image", "file is not a valid image")
+	ErrMediaNotOwned   = apperror.InvalidInput("invalid_media", "media not found or belongs to another user")
+)
+
+type Service struct {
+	store     MediaStore
+	repo      mediaDB.MediaRepository
+	processor *processor
+	publicURL string
+	maxSize   int
+	logger    *slog.Logger
+}
+
+func NewService(store MediaStore, repo mediaDB.MediaRepository, cfg config.MediaConfig, logger *slog.Logger) *Service {
+	return &Service{
+		store:     store,
+		repo:      repo,
+		processor: newProcessor(cfg.MaxPixels, cfg.JPEGQuality, cfg.ThumbnailSizes),
+		publicURL: strings.TrimRight(cfg.PublicURL, "/"),
+		maxSize:   cfg.MaxUploadSize,
+		logger:    logger,
+	}
+}
+
+// MaxUploadSize возвращает максимальный размер загружаемого файла в байтах
+func (s *Service) MaxUploadSize() int {
+	return s.maxSize
+}
+
+// URL возвращает адрес, по которому клиенты получают файл
+func (s *Service) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// Upload сохраняет изображение от имени ownerID. Возвращает ErrFileTooLarge,
+// ErrImageTooLarge, ErrUnsupportedType или ErrInvalidImage, если файл не
+// прошел проверку.
+func (s *Service) Upload(ctx context.Context, ownerID int, r io.Reader) (*mediaDB.Media, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(s.maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > s.maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	original, thumbnails, err := s.processor.process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := newKeyPrefix(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(original.data)
+	media := &mediaDB.Media{
+		OwnerID:     ownerID,
+		StorageKey:  fmt.Sprintf("%s/original.%s", prefix, original.ext),
+		ContentType: original.contentType,
+		Size:        int64(len(original.data)),
+		Width:       original.width,
+		Height:      original.height,
+		Checksum:    hex.EncodeToString(checksum[:]),
+		Thumbnails:  make([]mediaDB.Thumbnail, 0, len(thumbnails)),
+	}
+
+	files := map[string]*encodedImage{media.StorageKey: original}
+	for _, size := range s.processor.thumbnailSizes {
+		thumbnail, ok := thumbnails[size]
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%d.%s", prefix, size, thumbnail.ext)
+		media.Thumbnails = append(media.Thumbnails, mediaDB.Thumbnail{
+			Size:       size,
+			StorageKey: key,
+			Width:      thumbnail.width,
+			Height:     thumbnail.height,
+		})
+		files[key] = thumbnail
+	}
+
+	// Файлы сохраняются до записи в базу: запись без файлов была бы битой
+	// ссылкой, а файлы без записи удаляются здесь же
+	for _, key := range media.Keys() {
+		file := files[key]
+		if err := s.store.Put(ctx, key, bytes.NewReader(file.data), int64(len(file.data)), file.contentType); err != nil {
+			s.deleteFiles(ctx, media.Keys())
+			return nil, fmt.Errorf("failed to store media file: %w", err)
+		}
+	}
+
+	if err := s.repo.Create(ctx, media); err != nil {
+		s.deleteFiles(ctx, media.Keys())
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// Delete удаляет изображение вместе с файлами. Ссылки из профилей, постов
+// и машин снимаются базой данных.
+func (s *Service) Delete(ctx context.Context, media *mediaDB.Media) error {
+	if err := s.repo.Delete(ctx, media.ID); err != nil {
+		return err
+	}
+
+	s.deleteFiles(ctx, media.Keys())
+	return nil
+}
+
+// CheckOwned проверяет, что все изображения существуют и загружены
+// ownerID, прежде чем привязать их к профилю, посту или машине
+func (s *Service) CheckOwned(ctx context.Context, ownerID int, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	unique := slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(unique) != len(ids) {
+		return apperror.InvalidInput("duplicate_media", "media ids must be unique")
+	}
+
+	media, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(media) != len(ids) {
+		return ErrMediaNotOwned
+	}
+
+	for _, m := range media {
+		if m.OwnerID != ownerID {
+			return ErrMediaNotOwned
+		}
+	}
+
+	return nil
+}
+
+// deleteFiles удаляет файлы без учета отмены запроса. Ошибки только
+// логируются: оставшиеся файлы ни на что не ссылаются.
+func (s *Service) deleteFiles(ctx context.Context, keys []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete media file", "key", key, "error", err)
+		}
+	}
+}
+
+// newKeyPrefix возвращает случайный префикс ключей файлов изображения.
+// Ключи не угадываются, поэтому список файлов пользователя нельзя перебрать.
+func newKeyPrefix(ownerID int) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s", ownerID, hex.EncodeToString(id)), nil
+}
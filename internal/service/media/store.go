@@ -0,0 +1,15 @@
+package media
+
+import (
+	"context"
+	"io"
+)
+
+// MediaStore хранит файлы изображений по ключу вида "<owner>/<id>/<name>".
+// Ключи создает только Service, поэтому реализации могут считать их
+// безопасными путями.
+type MediaStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete удаляет файл; отсутствие файла не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,123 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testParams - минимальные параметры, чтобы тесты не тратили время на хеширование
+var testParams = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+const (
+	// testSalt и testKey - 16 и 32 байта в base64 без выравнивания
+	testSalt = "c2FsdHNhbHRzYWx0c2FsdA"
+	testKey  = "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+)
+
+func TestDecodeArgon2id(t *testing.T) {
+	tests := []struct {
+		name   string
+		hash   string
+		params Argon2Params
+		err    bool
+	}{
+		{
+			name:   "valid",
+			hash:   "$argon2id$v=19$m=19456,t=2,p=1$" + testSalt + "$" + testKey,
+			params: Argon2Params{Memory: 19456, Iterations: 2, Parallelism: 1},
+		},
+		{name: "empty", hash: "", err: true},
+		{name: "bcrypt hash", hash: "$2a$10$abcdefghijklmnopqrstuuabcdefghijklmnopqrstuvwxyz01234", err: true},
+		{name: "argon2i variant", hash: "$argon2i$v=19$m=19456,t=2,p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "missing key", hash: "$argon2id$v=19$m=19456,t=2,p=1$" + testSalt, err: true},
+		{name: "extra part", hash: "$argon2id$v=19$m=19456,t=2,p=1$" + testSalt + "$" + testKey + "$x", err: true},
+		{name: "unsupported version", hash: "$argon2id$v=16$m=19456,t=2,p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "malformed version", hash: "$argon2id$version=19$m=19456,t=2,p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "malformed params", hash: "$argon2id$v=19$m=19456;t=2;p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "non-numeric memory", hash: "$argon2id$v=19$m=lots,t=2,p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "zero iterations", hash: "$argon2id$v=19$m=19456,t=0,p=1$" + testSalt + "$" + testKey, err: true},
+		{name: "zero parallelism", hash: "$argon2id$v=19$m=19456,t=2,p=0$" + testSalt + "$" + testKey, err: true},
+		{name: "parallelism overflow", hash: "$argon2id$v=19$m=19456,t=2,p=256$" + testSalt + "$" + testKey, err: true},
+		{name: "invalid salt encoding", hash: "$argon2id$v=19$m=19456,t=2,p=1$!!!$" + testKey, err: true},
+		{name: "padded key", hash: "$argon2id$v=19$m=19456,t=2,p=1$" + testSalt + "$" + testKey + "=", err: true},
+		{name: "empty key", hash: "$argon2id$v=19$m=19456,t=2,p=1$" + testSalt + "$", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeArgon2id(tt.hash)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidHash) {
+					t.Fatalf("decodeArgon2id() error = %v, want ErrInvalidHash", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("decodeArgon2id() error = %v", err)
+			}
+			if decoded.params != tt.params {
+				t.Errorf("params = %+v, want %+v", decoded.params, tt.params)
+			}
+			if len(decoded.salt) != argon2SaltLength {
+				t.Errorf("salt length = %d, want %d", len(decoded.salt), argon2SaltLength)
+			}
+			if len(decoded.key) != argon2KeyLength {
+				t.Errorf("key length = %d, want %d", len(decoded.key), argon2KeyLength)
+			}
+		})
+	}
+}
+
+func TestArgon2idNeedsRehash(t *testing.T) {
+	hash, err := NewArgon2id(testParams).Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	shortKey := hash[:strings.LastIndex(hash, "$")+1] + "a2V5"
+
+	tests := []struct {
+		name   string
+		params Argon2Params
+		hash   string
+		want   bool
+	}{
+		{name: "same params", params: testParams, hash: hash, want: false},
+		{name: "memory changed", params: Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1}, hash: hash, want: true},
+		{name: "iterations changed", params: Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1}, hash: hash, want: true},
+		{name: "parallelism changed", params: Argon2Params{Memory: 64, Iterations: 1, Parallelism: 2}, hash: hash, want: true},
+		{name: "short key", params: testParams, hash: shortKey, want: true},
+		{name: "malformed hash", params: testParams, hash: "$argon2id$v=19$broken", want: true},
+		{name: "bcrypt hash", params: testParams, hash: "$2a$10$abcdefghijklmnopqrstuuabcdefghijklmnopqrstuvwxyz01234", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewArgon2id(tt.params).NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgon2idVerify(t *testing.T) {
+	hasher := NewArgon2id(testParams)
+
+	hash, err := hasher.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	// Хеш проверяется по параметрам из самого хеша, а не текущим
+	ok, err := NewArgon2id(Argon2Params{Memory: 128, Iterations: 3, Parallelism: 2}).Verify(hash, "secret")
+	if err != nil || !ok {
+		t.Errorf("Verify(correct password) = %v, %v, want true, nil", ok, err)
+	}
+
+	ok, err = hasher.Verify(hash, "wrong")
+	if err != nil || ok {
+		t.Errorf("Verify(wrong password) = %v, %v, want false, nil", ok, err)
+	}
+}
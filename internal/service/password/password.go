@@ -0,0 +1,90 @@
+// Пакет password хеширует и проверяет пароли пользователей.
+//
+// Новые пароли хешируются алгоритмом из конфигурации, а проверяются хеши
+// любого поддерживаемого алгоритма, поэтому смена алгоритма или его
+// параметров не ломает вход для существующих пользователей. Устаревшие
+// хеши пересчитываются при успешном входе (см. NeedsRehash).
+package password
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+)
+
+// ErrInvalidHash возвращается, если хеш поврежден или создан неизвестным
+// алгоритмом
+var ErrInvalidHash = errors.New("invalid password hash")
+
+type PasswordHasher interface {
+	// Hash возвращает хеш пароля вместе с солью и параметрами алгоритма
+	Hash(password string) (string, error)
+	// Verify сообщает, соответствует ли пароль хешу. Несовпадение пароля
+	// не является ошибкой.
+	Verify(hash, password string) (bool, error)
+	// NeedsRehash сообщает, что хеш создан другим алгоритмом или с другими
+	// параметрами и его стоит пересчитать
+	NeedsRehash(hash string) bool
+}
+
+// algorithm - реализация одного алгоритма хеширования
+type algorithm interface {
+	PasswordHasher
+	// recognizes сообщает, создан ли хеш этим алгоритмом
+	recognizes(hash string) bool
+}
+
+// Hasher хеширует пароли текущим алгоритмом и проверяет хеши всех
+// поддерживаемых алгоритмов
+type Hasher struct {
+	current    algorithm
+	algorithms []algorithm
+}
+
+func NewHasher(cfg config.PasswordConfig) (*Hasher, error) {
+	bcryptHasher := NewBcrypt(cfg.BcryptCost)
+	argon2Hasher := NewArgon2id(Argon2Params{
+		Memory:      cfg.Argon2.MemoryKiB,
+		Iterations:  cfg.Argon2.Iterations,
+		Parallelism: cfg.Argon2.Parallelism,
+	})
+
+	h := &Hasher{algorithms: []algorithm{argon2Hasher, bcryptHasher}}
+	switch cfg.Algorithm {
+	case config.PasswordAlgorithmArgon2id:
+		h.current = argon2Hasher
+	case config.PasswordAlgorithmBcrypt:
+		h.current = bcryptHasher
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %q", cfg.Algorithm)
+	}
+
+	return h, nil
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *Hasher) Verify(hash, password string) (bool, error) {
+	alg := h.algorithm(hash)
+	if alg == nil {
+		return false, ErrInvalidHash
+	}
+	return alg.Verify(hash, password)
+}
+
+func (h *Hasher) NeedsRehash(hash string) bool {
+	alg := h.algorithm(hash)
+	return alg != h.current || alg.NeedsRehash(hash)
+}
+
+func (h *Hasher) algorithm(hash string) algorithm {
+	for _, alg := range h.algorithms {
+		if alg.recognizes(hash) {
+			return alg
+		}
+	}
+	return nil
+}
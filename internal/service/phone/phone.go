@@ -0,0 +1,41 @@
+package phone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/nyaruka/phonenumbers"
+)
+
+var ErrInvalidPhone = apperror.InvalidInput("invalid_phone", "invalid phone number")
+
+// Normalizer приводит номера телефонов к формату E.164. Номера без кода
+// страны разбираются по правилам региона по умолчанию, поэтому для RU
+// "+7 999 123-45-67", "89991234567" и "79991234567" дают "+79991234567".
+type Normalizer struct {
+	defaultRegion string
+}
+
+func NewNormalizer(defaultRegion string) (*Normalizer, error) {
+	region := strings.ToUpper(defaultRegion)
+	if !phonenumbers.GetSupportedRegions()[region] {
+		return nil, fmt.Errorf("unsupported phone region: %q", defaultRegion)
+	}
+
+	return &Normalizer{defaultRegion: region}, nil
+}
+
+// Normalize возвращает номер в формате E.164 или ErrInvalidPhone
+func (n *Normalizer) Normalize(raw string) (string, error) {
+	number, err := phonenumbers.Parse(raw, n.defaultRegion)
+	if err != nil {
+		return "", ErrInvalidPhone
+	}
+
+	if !phonenumbers.IsValidNumber(number) {
+		return "", ErrInvalidPhone
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
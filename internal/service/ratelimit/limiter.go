@@ -0,0 +1,166 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Limiter ограничивает частоту запросов по произвольным ключам
+type Limiter struct {
+	store Store
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Allow забирает токен из корзины key с лимитом limit
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return l.store.Take(ctx, key, limit, time.Now())
+}
+
+// PhoneLimiter ограничивает частоту попыток для одного номера телефона,
+// например попыток ввести код сброса пароля
+type PhoneLimiter struct {
+	store Store
+	scope string
+	limit Limit
+}
+
+func NewPhoneLimiter(store Store, scope string, limit Limit) *PhoneLimiter {
+	return &PhoneLimiter{
+		store: store,
+		scope: scope,
+		limit: limit,
+	}
+}
+
+// Check забирает токен из корзины номера. Если попытка не разрешена,
+// возвращает время, через которое ее можно повторить.
+func (l *PhoneLimiter) Check(ctx context.Context, phone string) (time.Duration, error) {
+	result, err := l.store.Take(ctx, l.scope+":"+phone, l.limit, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RetryAfter, nil
+}
+
+// SignInGuard защищает вход от перебора паролей: ограничивает частоту
+// попыток для номера и блокирует номер и IP после серии неудач
+type SignInGuard struct {
+	store      Store
+	phoneLimit Limit
+	policy     LockoutPolicy
+}
+
+func NewSignInGuard(store Store, phoneLimit Limit, policy LockoutPolicy) *SignInGuard {
+	return &SignInGuard{
+		store:      store,
+		phoneLimit: phoneLimit,
+		policy:     policy,
+	}
+}
+
+// Check проверяет, можно ли выполнить попытку входа. Если нельзя,
+// возвращает время, через которое попытку можно повторить.
+func (g *SignInGuard) Check(ctx context.Context, phone, ip string) (time.Duration, error) {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, key := range []string{phoneKey(phone), ipKey(ip)} {
+		f, err := g.store.GetFailures(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if f.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, f.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, nil
+	}
+
+	result, err := g.store.Take(ctx, "sign_in_phone:"+phone, g.phoneLimit, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RetryAfter, nil
+}
+
+// Failed учитывает неудачную попытку входа. Если номер или IP
+// заблокированы, возвращает время до окончания блокировки.
+func (g *SignInGuard) Failed(ctx context.Context, phone, ip string) (time.Duration, error) {
+	now := time.Now()
+
+	var lockedFor time.Duration
+	for _, key := range []string{phoneKey(phone), ipKey(ip)} {
+		f, err := g.store.AddFailure(ctx, key, g.policy, now)
+		if err != nil {
+			return 0, err
+		}
+		if f.LockedUntil.After(now) {
+			lockedFor = max(lockedFor, f.LockedUntil.Sub(now))
+		}
+	}
+
+	return lockedFor, nil
+}
+
+// Succeeded сбрасывает неудачные попытки для номера. Счетчик IP не
+// сбрасывается, чтобы вход в свой аккаунт не обнулял перебор чужих.
+func (g *SignInGuard) Succeeded(ctx context.Context, phone string) error {
+	return g.store.ResetFailures(ctx, phoneKey(phone))
+}
+
+func phoneKey(phone string) string {
+	return "sign_in_failures:phone:" + phone
+}
+
+func ipKey(ip string) string {
+	return "sign_in_failures:ip:" + ip
+}
+
+// Cleaner периодически удаляет устаревшие записи хранилища
+type Cleaner struct {
+	store      Store
+	interval   time.Duration
+	staleAfter time.Duration
+	logger     *slog.Logger
+}
+
+func NewCleaner(store Store, interval, staleAfter time.Duration, logger *slog.Logger) *Cleaner {
+	return &Cleaner{
+		store:      store,
+		interval:   interval,
+		staleAfter: staleAfter,
+		logger:     logger,
+	}
+}
+
+// Run выполняет очистку с заданным интервалом, пока не будет отменен ctx
+func (c *Cleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := c.store.DeleteStale(ctx, time.Now().Add(-c.staleAfter))
+		if err != nil {
+			c.logger.ErrorContext(ctx, "failed to delete stale rate limit records", "error", err)
+			continue
+		}
+
+		if deleted > 0 {
+			c.logger.DebugContext(ctx, "deleted stale rate limit records", "count", deleted)
+		}
+	}
+}
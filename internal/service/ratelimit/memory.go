@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore хранит лимиты в памяти процесса. При нескольких репликах
+// каждая считает лимиты отдельно.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	failures map[string]Failures
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]Failures),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	tokens, result := take(b.tokens, b.updatedAt, limit, now)
+	b.tokens = tokens
+	b.updatedAt = now
+
+	return result, nil
+}
+
+func (s *MemoryStore) GetFailures(_ context.Context, key string) (Failures, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.failures[key], nil
+}
+
+func (s *MemoryStore) AddFailure(_ context.Context, key string, policy LockoutPolicy, now time.Time) (Failures, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := policy.next(s.failures[key], now)
+	s.failures[key] = f
+
+	return f, nil
+}
+
+func (s *MemoryStore) ResetFailures(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryStore) DeleteStale(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(before) {
+			delete(s.buckets, key)
+			deleted++
+		}
+	}
+
+	for key, f := range s.failures {
+		if f.LastFailureAt.Before(before) {
+			delete(s.failures, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
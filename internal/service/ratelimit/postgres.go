@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+)
+
+// PostgresStore хранит лимиты в Postgres, поэтому они общие для всех
+// реплик. Каждая операция выполняется в транзакции с блокировкой строки
+// ключа.
+type PostgresStore struct {
+	db      *sql.DB
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewPostgresStore(db *sql.DB, timeout time.Duration, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{db: db, timeout: timeout, logger: logger}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, database.LogError(ctx, s.logger, "ratelimit.Take", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key) DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, query, key, float64(limit.Requests), now); err != nil {
+		return Result{}, database.LogError(ctx, s.logger, "ratelimit.Take", err)
+	}
+
+	var (
+		tokens    float64
+		updatedAt time.Time
+	)
+
+	query = `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&tokens, &updatedAt); err != nil {
+		return Result{}, database.LogError(ctx, s.logger, "ratelimit.Take", err)
+	}
+
+	tokens, result := take(tokens, updatedAt, limit, now)
+
+	query = `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`
+	if _, err := tx.ExecContext(ctx, query, key, tokens, now); err != nil {
+		return Result{}, database.LogError(ctx, s.logger, "ratelimit.Take", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Result{}, database.LogError(ctx, s.logger, "ratelimit.Take", err)
+	}
+
+	return result, nil
+}
+
+func (s *PostgresStore) GetFailures(ctx context.Context, key string) (Failures, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	query := `
+        SELECT failures, last_failure_at, locked_until
+        FROM rate_limit_failures
+        WHERE key = $1`
+
+	f, err := scanFailures(s.db.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Failures{}, nil
+		}
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.GetFailures", err)
+	}
+
+	return f, nil
+}
+
+func (s *PostgresStore) AddFailure(ctx context.Context, key string, policy LockoutPolicy, now time.Time) (Failures, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.AddFailure", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO rate_limit_failures (key, failures, last_failure_at)
+        VALUES ($1, 0, $2)
+        ON CONFLICT (key) DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, query, key, now); err != nil {
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.AddFailure", err)
+	}
+
+	query = `
+        SELECT failures, last_failure_at, locked_until
+        FROM rate_limit_failures
+        WHERE key = $1
+        FOR UPDATE`
+
+	f, err := scanFailures(tx.QueryRowContext(ctx, query, key))
+	if err != nil {
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.AddFailure", err)
+	}
+
+	f = policy.next(f, now)
+
+	var lockedUntil sql.NullTime
+	if !f.LockedUntil.IsZero() {
+		lockedUntil = sql.NullTime{Time: f.LockedUntil, Valid: true}
+	}
+
+	query = `
+        UPDATE rate_limit_failures
+        SET failures = $2,
+            last_failure_at = $3,
+            locked_until = $4
+        WHERE key = $1`
+
+	if _, err := tx.ExecContext(ctx, query, key, f.Count, f.LastFailureAt, lockedUntil); err != nil {
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.AddFailure", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Failures{}, database.LogError(ctx, s.logger, "ratelimit.AddFailure", err)
+	}
+
+	return f, nil
+}
+
+func (s *PostgresStore) ResetFailures(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	query := `DELETE FROM rate_limit_failures WHERE key = $1`
+	_, err := s.db.ExecContext(ctx, query, key)
+	return database.LogError(ctx, s.logger, "ratelimit.ResetFailures", err)
+}
+
+func (s *PostgresStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var deleted int64
+	for _, query := range []string{
+		`DELETE FROM rate_limit_buckets WHERE updated_at < $1`,
+		`DELETE FROM rate_limit_failures WHERE last_failure_at < $1`,
+	} {
+		result, err := s.db.ExecContext(ctx, query, before)
+		if err != nil {
+			return deleted, database.LogError(ctx, s.logger, "ratelimit.DeleteStale", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, database.LogError(ctx, s.logger, "ratelimit.DeleteStale", err)
+		}
+		deleted += rowsAffected
+	}
+
+	return deleted, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFailures(row rowScanner) (Failures, error) {
+	var (
+		f           Failures
+		lockedUntil sql.NullTime
+	)
+
+	if err := row.Scan(&f.Count, &f.LastFailureAt, &lockedUntil); err != nil {
+		return Failures{}, err
+	}
+
+	if lockedUntil.Valid {
+		f.LockedUntil = lockedUntil.Time
+	}
+
+	return f, nil
+}
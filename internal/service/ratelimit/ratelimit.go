@@ -0,0 +1,120 @@
+// Пакет ratelimit ограничивает частоту запросов и блокирует вход после
+// серии неудачных попыток.
+//
+// Состояние хранится в Store. MemoryStore подходит для одной реплики,
+// PostgresStore разделяет лимиты между всеми репликами. Хранилище на
+// Redis или другой базе подключается реализацией Store.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit - корзина токенов емкостью Requests, которая полностью
+// наполняется за Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// rate возвращает скорость пополнения корзины в токенах в секунду
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result - результат попытки взять токен
+type Result struct {
+	Allowed bool
+	// RetryAfter - через сколько появится следующий токен, если запрос
+	// не разрешен
+	RetryAfter time.Duration
+}
+
+// Failures - неудачные попытки входа по одному ключу
+type Failures struct {
+	Count         int
+	LastFailureAt time.Time
+	// LockedUntil - время окончания блокировки; нулевое, если блокировки нет
+	LockedUntil time.Time
+}
+
+// LockoutPolicy задает прогрессивную блокировку: после Threshold неудач
+// ключ блокируется на Base, каждая следующая неудача удваивает блокировку,
+// но не больше Max. Счетчик сбрасывается, если неудач не было дольше Window.
+type LockoutPolicy struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Window    time.Duration
+}
+
+// Store хранит корзины токенов и счетчики неудачных попыток. Методы должны
+// быть атомарными относительно одного ключа.
+type Store interface {
+	// Take забирает токен из корзины key
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// GetFailures возвращает неудачные попытки по key
+	GetFailures(ctx context.Context, key string) (Failures, error)
+	// AddFailure учитывает неудачную попытку и применяет policy
+	AddFailure(ctx context.Context, key string, policy LockoutPolicy, now time.Time) (Failures, error)
+	// ResetFailures сбрасывает неудачные попытки и блокировку key
+	ResetFailures(ctx context.Context, key string) error
+	// DeleteStale удаляет записи, не изменявшиеся с before
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+// take пополняет корзину с tokens токенами, обновленную в updatedAt, и
+// пытается взять из нее токен. Возвращает новое число токенов.
+func take(tokens float64, updatedAt time.Time, limit Limit, now time.Time) (float64, Result) {
+	capacity := float64(limit.Requests)
+
+	elapsed := now.Sub(updatedAt).Seconds()
+	if elapsed > 0 {
+		tokens += elapsed * limit.rate()
+	}
+	if tokens > capacity {
+		tokens = capacity
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, Result{Allowed: true}
+	}
+
+	wait := (1 - tokens) / limit.rate()
+	return tokens, Result{RetryAfter: time.Duration(wait * float64(time.Second))}
+}
+
+// next возвращает состояние после еще одной неудачной попытки
+func (p LockoutPolicy) next(f Failures, now time.Time) Failures {
+	if now.Sub(f.LastFailureAt) > p.Window {
+		f.Count = 0
+	}
+
+	f.Count++
+	f.LastFailureAt = now
+
+	if f.Count >= p.Threshold {
+		lock := p.Base
+		for i := p.Threshold; i < f.Count && lock < p.Max; i++ {
+			lock *= 2
+		}
+		if lock > p.Max {
+			lock = p.Max
+		}
+		f.LockedUntil = now.Add(lock)
+	}
+
+	return f
+}
+
+// StaleAfter возвращает, через сколько после последнего изменения запись
+// гарантированно не влияет на решения: корзины успевают наполниться, а
+// блокировки и счетчики неудач - истечь
+func StaleAfter(policy LockoutPolicy, limits ...Limit) time.Duration {
+	stale := max(policy.Window, policy.Max)
+	for _, limit := range limits {
+		stale = max(stale, limit.Period)
+	}
+	return stale
+}
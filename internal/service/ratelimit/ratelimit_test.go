@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTake(t *testing.T) {
+	now := time.Date(2024, 3, 20, 15, 0, 0, 0, time.UTC)
+	// 10 запросов в минуту: один токен каждые 6 секунд
+	limit := Limit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		updatedAt  time.Time
+		wantTokens float64
+		wantResult Result
+	}{
+		{
+			name:       "full bucket",
+			tokens:     10,
+			updatedAt:  now,
+			wantTokens: 9,
+			wantResult: Result{Allowed: true},
+		},
+		{
+			name:       "last token",
+			tokens:     1,
+			updatedAt:  now,
+			wantTokens: 0,
+			wantResult: Result{Allowed: true},
+		},
+		{
+			name:       "empty bucket",
+			tokens:     0,
+			updatedAt:  now,
+			wantTokens: 0,
+			wantResult: Result{RetryAfter: 6 * time.Second},
+		},
+		{
+			name:       "partial token",
+			tokens:     0.5,
+			updatedAt:  now,
+			wantTokens: 0.5,
+			wantResult: Result{RetryAfter: 3 * time.Second},
+		},
+		{
+			name:       "refill",
+			tokens:     0,
+			updatedAt:  now.Add(-12 * time.Second),
+			wantTokens: 1,
+			wantResult: Result{Allowed: true},
+		},
+		{
+			name:       "partial refill",
+			tokens:     0,
+			updatedAt:  now.Add(-3 * time.Second),
+			wantTokens: 0.5,
+			wantResult: Result{RetryAfter: 3 * time.Second},
+		},
+		{
+			name:       "refill clamped to capacity",
+			tokens:     5,
+			updatedAt:  now.Add(-time.Hour),
+			wantTokens: 9,
+			wantResult: Result{Allowed: true},
+		},
+		{
+			name:       "tokens above capacity clamped",
+			tokens:     100,
+			updatedAt:  now,
+			wantTokens: 9,
+			wantResult: Result{Allowed: true},
+		},
+		{
+			// Часы другой реплики могут отставать: время назад не списывает токены
+			name:       "update in the future",
+			tokens:     1,
+			updatedAt:  now.Add(time.Minute),
+			wantTokens: 0,
+			wantResult: Result{Allowed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, result := take(tt.tokens, tt.updatedAt, limit, now)
+
+			if math.Abs(tokens-tt.wantTokens) > 1e-9 {
+				t.Errorf("tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if result.Allowed != tt.wantResult.Allowed {
+				t.Errorf("Allowed = %v, want %v", result.Allowed, tt.wantResult.Allowed)
+			}
+			if diff := result.RetryAfter - tt.wantResult.RetryAfter; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, tt.wantResult.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyNext(t *testing.T) {
+	now := time.Date(2024, 3, 20, 15, 0, 0, 0, time.UTC)
+	policy := LockoutPolicy{
+		Threshold: 3,
+		Base:      time.Minute,
+		Max:       10 * time.Minute,
+		Window:    time.Hour,
+	}
+
+	tests := []struct {
+		name       string
+		failures   Failures
+		wantCount  int
+		wantLocked time.Duration
+	}{
+		{
+			name:      "first failure",
+			failures:  Failures{},
+			wantCount: 1,
+		},
+		{
+			name:      "below threshold",
+			failures:  Failures{Count: 1, LastFailureAt: now.Add(-time.Minute)},
+			wantCount: 2,
+		},
+		{
+			name:       "threshold reached",
+			failures:   Failures{Count: 2, LastFailureAt: now.Add(-time.Minute)},
+			wantCount:  3,
+			wantLocked: time.Minute,
+		},
+		{
+			name:       "lockout doubles",
+			failures:   Failures{Count: 3, LastFailureAt: now.Add(-time.Minute)},
+			wantCount:  4,
+			wantLocked: 2 * time.Minute,
+		},
+		{
+			name:       "lockout doubles again",
+			failures:   Failures{Count: 5, LastFailureAt: now.Add(-time.Minute)},
+			wantCount:  6,
+			wantLocked: 8 * time.Minute,
+		},
+		{
+			name:       "lockout capped at max",
+			failures:   Failures{Count: 6, LastFailureAt: now.Add(-time.Minute)},
+			wantCount:  7,
+			wantLocked: 10 * time.Minute,
+		},
+		{
+			name:       "many failures stay at max",
+			failures:   Failures{Count: 1000, LastFailureAt: now.Add(-time.Minute)},
+			wantCount:  1001,
+			wantLocked: 10 * time.Minute,
+		},
+		{
+			name:       "failure at window edge is counted",
+			failures:   Failures{Count: 2, LastFailureAt: now.Add(-time.Hour)},
+			wantCount:  3,
+			wantLocked: time.Minute,
+		},
+		{
+			name:      "window expired resets count",
+			failures:  Failures{Count: 10, LastFailureAt: now.Add(-time.Hour - time.Second), LockedUntil: now.Add(-time.Hour)},
+			wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.next(tt.failures, now)
+
+			if got.Count != tt.wantCount {
+				t.Errorf("Count = %d, want %d", got.Count, tt.wantCount)
+			}
+			if !got.LastFailureAt.Equal(now) {
+				t.Errorf("LastFailureAt = %v, want %v", got.LastFailureAt, now)
+			}
+
+			if tt.wantLocked == 0 {
+				if got.LockedUntil.After(now) {
+					t.Errorf("LockedUntil = %v, want no lockout", got.LockedUntil)
+				}
+				return
+			}
+			if want := now.Add(tt.wantLocked); !got.LockedUntil.Equal(want) {
+				t.Errorf("LockedUntil = %v, want %v", got.LockedUntil, want)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyNextMaxBelowBase(t *testing.T) {
+	now := time.Date(2024, 3, 20, 15, 0, 0, 0, time.UTC)
+	policy := LockoutPolicy{Threshold: 1, Base: time.Hour, Max: time.Minute, Window: time.Hour}
+
+	got := policy.next(Failures{}, now)
+	if want := now.Add(time.Minute); !got.LockedUntil.Equal(want) {
+		t.Errorf("LockedUntil = %v, want %v", got.LockedUntil, want)
+	}
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ExpiredSessionDeleter удаляет истекшие сессии
+type ExpiredSessionDeleter interface {
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Sweeper периодически удаляет истекшие сессии из базы
+type Sweeper struct {
+	repo     ExpiredSessionDeleter
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewSweeper(repo ExpiredSessionDeleter, interval time.Duration, logger *slog.Logger) *Sweeper {
+	return &Sweeper{
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run выполняет очистку сразу и затем с заданным интервалом,
+// пока не будет отменен ctx
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	deleted, err := s.repo.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete expired sessions", "error", err)
+		return
+	}
+
+	if deleted > 0 {
+		s.logger.InfoContext(ctx, "deleted expired sessions", "count", deleted)
+	}
+}
@@ -0,0 +1,79 @@
+package sms
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// SMSSender отправляет SMS-сообщения. Реализации для конкретных
+// SMS-шлюзов подключаются в cmd/api/main.go. purpose - назначение
+// сообщения (например, код подтверждения номера) для логов и метрик.
+type SMSSender interface {
+	Send(ctx context.Context, phone, purpose, message string) error
+}
+
+// LogSender только пишет в лог, что сообщение отправлено, не отправляя его.
+// Текст сообщения не логируется: в нем одноразовый код, и доступ к логам
+// не должен давать доступ к аккаунтам. Предназначен для локальной разработки.
+type LogSender struct {
+	logger *slog.Logger
+}
+
+func NewLogSender(logger *slog.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, phone, purpose, message string) error {
+	s.logger.InfoContext(ctx, "sms sent", "phone", phone, "purpose", purpose)
+	return nil
+}
+
+// Message описывает сообщение, сохраненное MemorySender
+type Message struct {
+	Phone   string
+	Purpose string
+	Text    string
+}
+
+// MemorySender сохраняет сообщения в памяти. Предназначен для тестов.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(ctx context.Context, phone, purpose, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, Message{Phone: phone, Purpose: purpose, Text: message})
+	return nil
+}
+
+// Messages возвращает копию отправленных сообщений
+func (s *MemorySender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+	return messages
+}
+
+// Last возвращает последнее сообщение, отправленное на номер
+func (s *MemorySender) Last(phone string) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if s.messages[i].Phone == phone {
+			return s.messages[i], true
+		}
+	}
+
+	return Message{}, false
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+)
+
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioSender отправляет сообщения через Twilio Messaging API
+type TwilioSender struct {
+	client     *http.Client
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+}
+
+func NewTwilioSender(cfg config.TwilioConfig, timeout time.Duration) *TwilioSender {
+	return &TwilioSender{
+		client:     &http.Client{Timeout: timeout},
+		baseURL:    twilioBaseURL,
+		accountSID: cfg.AccountSID,
+		authToken:  cfg.AuthToken,
+		from:       cfg.From,
+	}
+}
+
+// twilioError - тело ответа Twilio с ошибкой
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *TwilioSender) Send(ctx context.Context, phone, purpose, message string) error {
+	form := url.Values{
+		"To":   {phone},
+		"From": {s.from},
+		"Body": {message},
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	// Текст сообщения в ошибку не попадает: в нем одноразовый код
+	var apiErr twilioError
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr); err != nil || apiErr.Message == "" {
+		return fmt.Errorf("failed to send %s sms: twilio status %d", purpose, resp.StatusCode)
+	}
+
+	return fmt.Errorf("failed to send %s sms: twilio status %d, code %d: %s", purpose, resp.StatusCode, apiErr.Code, apiErr.Message)
+}
@@ -1,6 +1,10 @@
 package token
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -8,7 +12,9 @@ import (
 )
 
 type TokenManager struct {
-	signingKey string
+	signingKey      string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
 type TokenClaims struct {
@@ -16,19 +22,26 @@ type TokenClaims struct {
 	UserID int `json:"user_id"`
 }
 
-func NewTokenManager(signingKey string) (*TokenManager, error) {
+func NewTokenManager(signingKey string, accessTokenTTL, refreshTokenTTL time.Duration) (*TokenManager, error) {
 	if signingKey == "" {
 		return nil, fmt.Errorf("empty signing key")
 	}
 
-	return &TokenManager{signingKey: signingKey}, nil
+	if accessTokenTTL <= 0 || refreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("token ttl must be positive")
+	}
+
+	return &TokenManager{
+		signingKey:      signingKey,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}, nil
 }
 
 func (m *TokenManager) GenerateAccessToken(userID int) (string, error) {
-	// Срок действия access token - 15 минут
 	claims := TokenClaims{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(15 * time.Minute).Unix(),
+			ExpiresAt: time.Now().Add(m.accessTokenTTL).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
 		UserID: userID,
@@ -38,15 +51,21 @@ func (m *TokenManager) GenerateAccessToken(userID int) (string, error) {
 	return token.SignedString([]byte(m.signingKey))
 }
 
-func (m *TokenManager) GenerateRefreshToken() (string, error) {
-	// Генерируем случайный refresh token
-	token := jwt.New(jwt.SigningMethodHS256)
+// GenerateRefreshToken генерирует непрозрачный случайный refresh token и
+// время его истечения. В базе хранится только хеш токена (см. HashRefreshToken).
+func (m *TokenManager) GenerateRefreshToken() (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["exp"] = time.Now().Add(720 * time.Hour).Unix() // 30 дней
-	claims["iat"] = time.Now().Unix()
+	return base64.RawURLEncoding.EncodeToString(b), time.Now().Add(m.refreshTokenTTL), nil
+}
 
-	return token.SignedString([]byte(m.signingKey))
+// HashRefreshToken возвращает хеш refresh token для хранения и поиска в базе
+func HashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
 }
 
 func (m *TokenManager) ParseToken(accessToken string) (int, error) {
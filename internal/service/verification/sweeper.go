@@ -0,0 +1,59 @@
+package verification
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ExpiredCodeDeleter удаляет истекшие коды
+type ExpiredCodeDeleter interface {
+	DeleteExpiredCodes(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Sweeper периодически удаляет истекшие коды из базы. Коды хранятся еще
+// retention после истечения: по ним считаются лимиты отправки.
+type Sweeper struct {
+	repo      ExpiredCodeDeleter
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+func NewSweeper(repo ExpiredCodeDeleter, interval, retention time.Duration, logger *slog.Logger) *Sweeper {
+	return &Sweeper{
+		repo:      repo,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Run выполняет очистку сразу и затем с заданным интервалом,
+// пока не будет отменен ctx
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	deleted, err := s.repo.DeleteExpiredCodes(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete expired verification codes", "error", err)
+		return
+	}
+
+	if deleted > 0 {
+		s.logger.InfoContext(ctx, "deleted expired verification codes", "count", deleted)
+	}
+}
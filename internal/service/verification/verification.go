@@ -0,0 +1,138 @@
+package verification
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/config"
+	verificationDB "github.com/NikitaBelov-mobile/car-social/internal/database/verification"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/sms"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const codeLength = 6
+
+var (
+	ErrTooManyRequests = apperror.TooManyRequests("too_many_code_requests", "too many code requests")
+	ErrTooManyAttempts = apperror.TooManyRequests("too_many_attempts", "too many attempts")
+	ErrInvalidCode     = apperror.InvalidInput("invalid_code", "invalid code")
+	ErrCodeExpired     = apperror.InvalidInput("code_expired", "code expired")
+)
+
+// Service отправляет одноразовые коды по SMS и проверяет их
+type Service struct {
+	repo   verificationDB.VerificationRepository
+	sender sms.SMSSender
+	cfg    config.OTPConfig
+}
+
+func NewService(repo verificationDB.VerificationRepository, sender sms.SMSSender, cfg config.OTPConfig) *Service {
+	return &Service{
+		repo:   repo,
+		sender: sender,
+		cfg:    cfg,
+	}
+}
+
+// RequestCode генерирует новый код для номера и отправляет его по SMS.
+// Возвращает ErrTooManyRequests, если с прошлой отправки прошло меньше
+// ResendInterval или за последний час исчерпан лимит MaxCodesPerHour.
+func (s *Service) RequestCode(ctx context.Context, phone, purpose, message string) error {
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	codeHash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// Лимиты проверяются атомарно вместе с сохранением кода, чтобы
+	// параллельные запросы не отправили больше SMS, чем разрешено
+	err = s.repo.CreateCode(ctx, &verificationDB.Code{
+		Phone:     phone,
+		Purpose:   purpose,
+		CodeHash:  string(codeHash),
+		ExpiresAt: time.Now().Add(s.cfg.CodeTTL),
+	}, verificationDB.Limits{
+		ResendInterval: s.cfg.ResendInterval,
+		MaxPerHour:     s.cfg.MaxCodesPerHour,
+	})
+	if err != nil {
+		if errors.Is(err, verificationDB.ErrCodeLimitExceeded) {
+			return ErrTooManyRequests
+		}
+		return err
+	}
+
+	return s.sender.Send(ctx, phone, purpose, fmt.Sprintf(message, code))
+}
+
+// VerifyCode проверяет последний отправленный на номер код и при успехе
+// помечает его использованным
+func (s *Service) VerifyCode(ctx context.Context, phone, purpose, code string) error {
+	last, err := s.repo.GetLatestCode(ctx, phone, purpose)
+	if err != nil {
+		if errors.Is(err, verificationDB.ErrCodeNotFound) {
+			return ErrInvalidCode
+		}
+		return err
+	}
+
+	if last.ConsumedAt != nil {
+		return ErrInvalidCode
+	}
+
+	if !last.ExpiresAt.After(time.Now()) {
+		return ErrCodeExpired
+	}
+
+	// Попытка засчитывается до сравнения: иначе параллельные запросы успели
+	// бы пройти проверку лимита, пока идет медленное сравнение bcrypt
+	if err := s.repo.ClaimAttempt(ctx, last.ID, s.cfg.MaxAttempts); err != nil {
+		if errors.Is(err, verificationDB.ErrAttemptsExhausted) {
+			return ErrTooManyAttempts
+		}
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(last.CodeHash), []byte(code)); err != nil {
+		return ErrInvalidCode
+	}
+
+	if err := s.repo.ConsumeCode(ctx, last.ID); err != nil {
+		if errors.Is(err, verificationDB.ErrCodeConsumed) {
+			return ErrInvalidCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Retention возвращает, сколько хранить код после истечения: по кодам за
+// последний час и последнему коду проверяются лимиты отправки
+func Retention(cfg config.OTPConfig) time.Duration {
+	return max(time.Hour, cfg.ResendInterval)
+}
+
+// generateCode возвращает случайный цифровой код длины codeLength
+func generateCode() (string, error) {
+	upper := big.NewInt(1)
+	for i := 0; i < codeLength; i++ {
+		upper.Mul(upper, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, upper)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", codeLength, n), nil
+}
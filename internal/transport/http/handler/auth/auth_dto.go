@@ -6,12 +6,14 @@ type SignUpRequest struct {
 }
 
 type SignInRequest struct {
-	Phone    string `json:"phone" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Phone      string `json:"phone" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name,omitempty" binding:"max=255" example:"iPhone 15"`
 }
 
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
+	DeviceName   string `json:"device_name,omitempty" binding:"max=255" example:"iPhone 15"`
 }
 
 type TokensResponse struct {
@@ -19,11 +21,6 @@ type TokensResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-// ErrorResponse представляет структуру ответа с ошибкой
-type ErrorResponse struct {
-	Error string `json:"error" example:"описание ошибки"`
-}
-
 // Response представляет структуру успешного ответа
 type Response struct {
 	Message string `json:"message" example:"операция выполнена успешно"`
@@ -31,5 +28,38 @@ type Response struct {
 
 // LogoutRequest представляет структуру запроса для выхода
 type LogoutRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIs..."`
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q9cXH1Lr0yR6b2mJ0n4Vd8kPz3WfTgU5aY7eBsQiOxM"`
+}
+
+// SessionResponse представляет активную сессию (устройство) пользователя
+type SessionResponse struct {
+	ID         int    `json:"id" example:"1"`
+	DeviceName string `json:"device_name" example:"iPhone 15"`
+	UserAgent  string `json:"user_agent" example:"CarSocial/1.0 (iOS 17.4)"`
+	IP         string `json:"ip" example:"192.168.1.10"`
+	CreatedAt  string `json:"created_at" example:"2024-03-20 15:04:05"`
+	LastUsedAt string `json:"last_used_at" example:"2024-03-21 09:15:00"`
+}
+
+// PhoneCodeRequest представляет запрос на отправку кода подтверждения
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" binding:"required" example:"+79991234567"`
+}
+
+// PhoneVerifyRequest представляет запрос на подтверждение номера телефона
+type PhoneVerifyRequest struct {
+	Phone string `json:"phone" binding:"required" example:"+79991234567"`
+	Code  string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// ForgotPasswordRequest представляет запрос на отправку кода сброса пароля
+type ForgotPasswordRequest struct {
+	Phone string `json:"phone" binding:"required" example:"+79991234567"`
+}
+
+// ResetPasswordRequest представляет запрос на установку нового пароля по коду
+type ResetPasswordRequest struct {
+	Phone       string `json:"phone" binding:"required" example:"+79991234567"`
+	Code        string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+	NewPassword string `json:"new_password" binding:"required,min=6" example:"newpassword123"`
 }
@@ -1,14 +1,25 @@
 package auth
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
 	authDB "github.com/NikitaBelov-mobile/car-social/internal/database/auth"
 	userDB "github.com/NikitaBelov-mobile/car-social/internal/database/user"
+	verificationDB "github.com/NikitaBelov-mobile/car-social/internal/database/verification"
+	"github.com/NikitaBelov-mobile/car-social/internal/metrics"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/password"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/phone"
 	"github.com/NikitaBelov-mobile/car-social/internal/service/token"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/verification"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // type Handler struct {
@@ -17,33 +28,110 @@ import (
 // }
 
 type AuthRepository interface {
-	CreateSession(session *authDB.Session) error
-	GetSessionByRefreshToken(refreshToken string) (*authDB.Session, error)
-	DeleteSession(refreshToken string) error
-	DeleteUserSessions(userID int) error
+	CreateSession(ctx context.Context, session *authDB.Session) error
+	GetSessionByID(ctx context.Context, id int) (*authDB.Session, error)
+	GetSessionByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*authDB.Session, error)
+	ListActiveUserSessions(ctx context.Context, userID int, now time.Time) ([]*authDB.Session, error)
+	RotateSession(ctx context.Context, oldSessionID int, newSession *authDB.Session) error
+	DeleteSessionFamily(ctx context.Context, familyID string) error
+	DeleteUserSessions(ctx context.Context, userID int) error
 }
 
+// SignInGuard защищает вход от перебора паролей
+type SignInGuard interface {
+	// Check возвращает время, через которое можно повторить попытку входа,
+	// или 0, если попытка разрешена
+	Check(ctx context.Context, phone, ip string) (time.Duration, error)
+	Failed(ctx context.Context, phone, ip string) (time.Duration, error)
+	Succeeded(ctx context.Context, phone string) error
+}
+
+// PhoneLimiter ограничивает частоту попыток для одного номера телефона
+type PhoneLimiter interface {
+	// Check возвращает время, через которое можно повторить попытку,
+	// или 0, если попытка разрешена
+	Check(ctx context.Context, phone string) (time.Duration, error)
+}
+
+// RateLimits - ограничения частоты для отдельных маршрутов
+type RateLimits struct {
+	SignUp gin.HandlerFunc
+	SignIn gin.HandlerFunc
+}
+
+var (
+	errInvalidCredentials  = apperror.Unauthorized("invalid_credentials", "invalid credentials")
+	errPhoneNotVerified    = apperror.Forbidden("phone_not_verified", "phone not verified")
+	errInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	errRefreshTokenExpired = apperror.Unauthorized("refresh_token_expired", "refresh token expired")
+	errRefreshTokenReuse   = apperror.Unauthorized("refresh_token_reuse", "refresh token reuse detected")
+	errSignInLocked        = apperror.TooManyRequests("sign_in_locked", "too many sign in attempts")
+	errResetPasswordLocked = apperror.TooManyRequests("reset_password_locked", "too many password reset attempts")
+)
+
 type Handler struct {
 	userRepo     userDB.UserRepository
 	authRepo     AuthRepository
 	tokenManager *token.TokenManager
+	verifier     *verification.Service
+	phones       *phone.Normalizer
+	passwords    password.PasswordHasher
+	guard        SignInGuard
+	resetGuard   PhoneLimiter
+	logger       *slog.Logger
+	metrics      *metrics.Auth
+
+	requirePhoneVerification bool
 }
 
-func NewHandler(userRepo userDB.UserRepository, authRepo AuthRepository, tokenManager *token.TokenManager) *Handler {
+func NewHandler(
+	userRepo userDB.UserRepository,
+	authRepo AuthRepository,
+	tokenManager *token.TokenManager,
+	verifier *verification.Service,
+	phones *phone.Normalizer,
+	passwords password.PasswordHasher,
+	guard SignInGuard,
+	resetGuard PhoneLimiter,
+	logger *slog.Logger,
+	metrics *metrics.Auth,
+	requirePhoneVerification bool,
+) *Handler {
 	return &Handler{
-		userRepo:     userRepo,
-		authRepo:     authRepo,
-		tokenManager: tokenManager,
+		userRepo:                 userRepo,
+		authRepo:                 authRepo,
+		tokenManager:             tokenManager,
+		verifier:                 verifier,
+		phones:                   phones,
+		passwords:                passwords,
+		guard:                    guard,
+		resetGuard:               resetGuard,
+		logger:                   logger,
+		metrics:                  metrics,
+		requirePhoneVerification: requirePhoneVerification,
 	}
 }
 
-func (h *Handler) Register(router *gin.RouterGroup) {
+// Register подключает публичные маршруты к router, а маршруты управления
+// сессиями, требующие access token, к protected
+func (h *Handler) Register(router *gin.RouterGroup, protected *gin.RouterGroup, limits RateLimits) {
 	auth := router.Group("/auth")
 	{
-		auth.POST("/sign-up", h.signUp)
-		auth.POST("/sign-in", h.signIn)
+		auth.POST("/sign-up", limits.SignUp, h.signUp)
+		auth.POST("/sign-in", limits.SignIn, h.signIn)
 		auth.POST("/refresh", h.refresh)
 		auth.POST("/logout", h.logout)
+		auth.POST("/phone/request-code", h.requestPhoneCode)
+		auth.POST("/phone/verify", h.verifyPhone)
+		auth.POST("/password/forgot", h.forgotPassword)
+		auth.POST("/password/reset", h.resetPassword)
+	}
+
+	sessions := protected.Group("/auth")
+	{
+		sessions.GET("/sessions", h.listSessions)         // Активные устройства
+		sessions.DELETE("/sessions/:id", h.revokeSession) // Завершение сессии устройства
+		sessions.POST("/logout-all", h.logoutAll)         // Выход со всех устройств
 	}
 }
 
@@ -55,40 +143,42 @@ func (h *Handler) Register(router *gin.RouterGroup) {
 // @Produce  json
 // @Param input body SignUpRequest true "Данные для регистрации"
 // @Success 201 {object} Response "успешная регистрация"
-// @Failure 400 {object} ErrorResponse "неверный формат данных"
-// @Failure 409 {object} ErrorResponse "пользователь уже существует"
-// @Failure 500 {object} ErrorResponse "внутренняя ошибка сервера"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 409 {object} response.ErrorResponse "пользователь уже существует"
+// @Failure 429 {object} response.ErrorResponse "слишком частые регистрации"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /auth/sign-up [post]
 func (h *Handler) signUp(c *gin.Context) {
 	var req SignUpRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(response.InvalidRequest(err))
 		return
 	}
 
-	existingUser, err := h.userRepo.GetByPhone(req.Phone)
-	if err == nil && existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "user already exists"})
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := h.passwords.Hash(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+		c.Error(err)
 		return
 	}
 
 	user := &userDB.User{
-		Phone:        req.Phone,
-		PasswordHash: string(hashedPassword),
+		Phone:        phoneNumber,
+		PasswordHash: passwordHash,
 	}
 
-	if err := h.userRepo.Create(user); err != nil {
-		fmt.Println("err", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
+	if err := h.userRepo.Create(c.Request.Context(), user); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.metrics.SignUp()
 	c.Status(http.StatusCreated)
 }
 
@@ -100,47 +190,97 @@ func (h *Handler) signUp(c *gin.Context) {
 // @Produce  json
 // @Param input body SignInRequest true "Данные для входа"
 // @Success 200 {object} TokensResponse "токены доступа"
-// @Failure 400 {object} ErrorResponse "неверный формат данных"
-// @Failure 401 {object} ErrorResponse "неверные учетные данные"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "неверные учетные данные"
+// @Failure 403 {object} response.ErrorResponse "номер телефона не подтвержден"
+// @Failure 429 {object} response.ErrorResponse "слишком много попыток входа"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /auth/sign-in [post]
 func (h *Handler) signIn(c *gin.Context) {
 	var req SignInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(response.InvalidRequest(err))
 		return
 	}
 
-	user, err := h.userRepo.GetByPhone(req.Phone)
+	phoneNumber, err := h.phones.Normalize(req.Phone)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		c.Error(err)
+		return
+	}
+
+	retryAfter, err := h.guard.Check(c.Request.Context(), phoneNumber, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if retryAfter > 0 {
+		h.metrics.SignInFailed(errSignInLocked.Code)
+		c.Error(apperror.WithRetryAfter(errSignInLocked, retryAfter))
+		return
+	}
+
+	user, err := h.userRepo.GetByPhone(c.Request.Context(), phoneNumber)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			h.signInFailed(c, phoneNumber)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	ok, err := h.passwords.Verify(user.PasswordHash, req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !ok {
+		h.logger.InfoContext(c.Request.Context(), "sign in failed: invalid password", "target_user_id", user.ID, "ip", c.ClientIP())
+		h.signInFailed(c, phoneNumber)
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	if err := h.guard.Succeeded(c.Request.Context(), phoneNumber); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if h.passwords.NeedsRehash(user.PasswordHash) {
+		h.rehashPassword(c, user, req.Password)
+	}
+
+	if h.requirePhoneVerification && user.PhoneVerifiedAt == nil {
+		h.metrics.SignInFailed(errPhoneNotVerified.Code)
+		c.Error(errPhoneNotVerified)
 		return
 	}
 
 	// Генерируем токены
 	accessToken, err := h.tokenManager.GenerateAccessToken(user.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate access token"})
+		c.Error(err)
 		return
 	}
 
-	refreshToken, err := h.tokenManager.GenerateRefreshToken()
+	refreshToken, expiresAt, err := h.tokenManager.GenerateRefreshToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate refresh token"})
+		c.Error(err)
 		return
 	}
 
 	session := &authDB.Session{
-		UserID:       user.ID,
-		RefreshToken: refreshToken,
+		UserID:           user.ID,
+		RefreshTokenHash: token.HashRefreshToken(refreshToken),
+		DeviceName:       req.DeviceName,
+		UserAgent:        c.Request.UserAgent(),
+		IP:               c.ClientIP(),
+		ExpiresAt:        expiresAt,
 	}
 
-	if err := h.authRepo.CreateSession(session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+	if err := h.authRepo.CreateSession(c.Request.Context(), session); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -150,6 +290,40 @@ func (h *Handler) signIn(c *gin.Context) {
 	})
 }
 
+// rehashPassword пересчитывает хеш, созданный устаревшим алгоритмом или
+// параметрами. Пароль уже проверен, поэтому ошибка не мешает входу.
+func (h *Handler) rehashPassword(c *gin.Context, user *userDB.User, plain string) {
+	passwordHash, err := h.passwords.Hash(plain)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to rehash password", "target_user_id", user.ID, "error", err)
+		return
+	}
+
+	if err := h.userRepo.ReplacePasswordHash(c.Request.Context(), user.ID, user.PasswordHash, passwordHash); err != nil {
+		return
+	}
+
+	user.PasswordHash = passwordHash
+	h.logger.InfoContext(c.Request.Context(), "password rehashed", "target_user_id", user.ID)
+}
+
+// signInFailed учитывает неудачную попытку входа. Блокировка, если она
+// наступила, сработает на следующей попытке, а на эту отвечаем как обычно.
+func (h *Handler) signInFailed(c *gin.Context, phoneNumber string) {
+	lockedFor, err := h.guard.Failed(c.Request.Context(), phoneNumber, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if lockedFor > 0 {
+		h.logger.WarnContext(c.Request.Context(), "sign in locked", "ip", c.ClientIP(), "locked_for", lockedFor.String())
+	}
+
+	h.metrics.SignInFailed(errInvalidCredentials.Code)
+	c.Error(errInvalidCredentials)
+}
+
 // Refresh godoc
 // @Summary Обновление токена
 // @Tags auth
@@ -158,56 +332,97 @@ func (h *Handler) signIn(c *gin.Context) {
 // @Produce  json
 // @Param input body RefreshRequest true "Refresh token"
 // @Success 200 {object} TokensResponse "новые токены"
-// @Failure 400 {object} ErrorResponse "неверный формат данных"
-// @Failure 401 {object} ErrorResponse "невалидный refresh token"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "невалидный или повторно использованный refresh token"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /auth/refresh [post]
 func (h *Handler) refresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(response.InvalidRequest(err))
 		return
 	}
 
-	session, err := h.authRepo.GetSessionByRefreshToken(req.RefreshToken)
+	session, err := h.authRepo.GetSessionByRefreshTokenHash(c.Request.Context(), token.HashRefreshToken(req.RefreshToken))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		if errors.Is(err, apperror.ErrNotFound) {
+			c.Error(errInvalidRefreshToken)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if !session.ExpiresAt.After(time.Now()) {
+		c.Error(errRefreshTokenExpired)
 		return
 	}
 
-	if err := h.authRepo.DeleteSession(req.RefreshToken); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh session"})
+	// Повторное использование уже обмененного токена означает его утечку:
+	// отзываем все сессии семейства
+	if session.Consumed {
+		h.revokeFamily(c, session)
 		return
 	}
 
 	// Генерируем новые токены
 	accessToken, err := h.tokenManager.GenerateAccessToken(session.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate access token"})
+		c.Error(err)
 		return
 	}
 
-	refreshToken, err := h.tokenManager.GenerateRefreshToken()
+	refreshToken, expiresAt, err := h.tokenManager.GenerateRefreshToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate refresh token"})
+		c.Error(err)
 		return
 	}
 
 	newSession := &authDB.Session{
-		UserID:       session.UserID,
-		RefreshToken: refreshToken,
+		UserID:           session.UserID,
+		RefreshTokenHash: token.HashRefreshToken(refreshToken),
+		DeviceName:       req.DeviceName,
+		UserAgent:        c.Request.UserAgent(),
+		IP:               c.ClientIP(),
+		ExpiresAt:        expiresAt,
 	}
 
-	if err := h.authRepo.CreateSession(newSession); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create new session"})
+	if err := h.authRepo.RotateSession(c.Request.Context(), session.ID, newSession); err != nil {
+		if errors.Is(err, authDB.ErrSessionConsumed) {
+			h.revokeFamily(c, session)
+			return
+		}
+		c.Error(err)
 		return
 	}
 
+	h.metrics.RefreshRotated()
 	c.JSON(http.StatusOK, TokensResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	})
 }
 
+// revokeFamily отзывает все сессии семейства при обнаружении повторного
+// использования refresh token
+func (h *Handler) revokeFamily(c *gin.Context, session *authDB.Session) {
+	h.logger.WarnContext(c.Request.Context(), "refresh token reuse detected",
+		"target_user_id", session.UserID,
+		"session_id", session.ID,
+		"family_id", session.FamilyID,
+		"ip", c.ClientIP(),
+	)
+	h.metrics.RefreshReused()
+
+	if err := h.authRepo.DeleteSessionFamily(c.Request.Context(), session.FamilyID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Error(errRefreshTokenReuse)
+}
+
 // Logout godoc
 // @Summary Выход из системы
 // @Tags auth
@@ -216,8 +431,10 @@ func (h *Handler) refresh(c *gin.Context) {
 // @Produce  json
 // @Param input body LogoutRequest true "Refresh token"
 // @Success 200 {object} Response "успешный выход"
-// @Failure 400 {object} ErrorResponse "неверный формат данных"
-// @Failure 500 {object} ErrorResponse "ошибка сервера"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "невалидный refresh token"
+// @Failure 500 {object} response.ErrorResponse "ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /auth/logout [post]
 func (h *Handler) logout(c *gin.Context) {
 	// Получаем refresh token из тела запроса
@@ -226,15 +443,378 @@ func (h *Handler) logout(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token не предоставлен"})
+		c.Error(apperror.InvalidInput("missing_refresh_token", "refresh token не предоставлен"))
+		return
+	}
+
+	session, err := h.authRepo.GetSessionByRefreshTokenHash(c.Request.Context(), token.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			c.Error(errInvalidRefreshToken)
+			return
+		}
+		c.Error(err)
 		return
 	}
 
-	// Удаляем сессию
-	if err := h.authRepo.DeleteSession(req.RefreshToken); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "ошибка при выходе из системы"})
+	// Удаляем сессию вместе со всем семейством ротаций
+	if err := h.authRepo.DeleteSessionFamily(c.Request.Context(), session.FamilyID); err != nil {
+		c.Error(err)
 		return
 	}
 
+	h.metrics.Logout("session")
 	c.JSON(http.StatusOK, gin.H{"message": "успешный выход из системы"})
 }
+
+// ListSessions godoc
+// @Summary Активные сессии
+// @Tags auth
+// @Description Список устройств, на которых выполнен вход в аккаунт текущего пользователя
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {array} SessionResponse
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/sessions [get]
+func (h *Handler) listSessions(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := h.authRepo.ListActiveUserSessions(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, SessionResponse{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt.Format("2006-01-02 15:04:05"),
+			LastUsedAt: session.LastUsedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession godoc
+// @Summary Завершение сессии устройства
+// @Tags auth
+// @Description Отзыв refresh token устройства текущего пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID сессии"
+// @Security BearerAuth
+// @Success 204 "сессия завершена"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "сессия не найдена"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) revokeSession(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	session, err := h.authRepo.GetSessionByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Чужие сессии не раскрываем и отвечаем так же, как на несуществующие
+	if session.UserID != userID {
+		c.Error(authDB.ErrSessionNotFound)
+		return
+	}
+
+	if err := h.authRepo.DeleteSessionFamily(c.Request.Context(), session.FamilyID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.metrics.Logout("device")
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Выход со всех устройств
+// @Tags auth
+// @Description Завершение всех сессий текущего пользователя. Выданные access token остаются действительными до истечения срока.
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} Response "успешный выход"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/logout-all [post]
+func (h *Handler) logoutAll(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authRepo.DeleteUserSessions(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.metrics.Logout("all")
+	c.JSON(http.StatusOK, gin.H{"message": "успешный выход со всех устройств"})
+}
+
+// RequestPhoneCode godoc
+// @Summary Запрос кода подтверждения телефона
+// @Tags auth
+// @Description Отправка одноразового SMS-кода для подтверждения номера телефона. Ответ не раскрывает, зарегистрирован ли номер.
+// @Accept  json
+// @Produce  json
+// @Param input body PhoneCodeRequest true "Номер телефона"
+// @Success 202 {object} Response "код отправлен"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/phone/request-code [post]
+func (h *Handler) requestPhoneCode(c *gin.Context) {
+	var req PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Код отправляем только зарегистрированным неподтвержденным номерам,
+	// но отвечаем одинаково, чтобы не раскрывать наличие аккаунта
+	user, err := h.userRepo.GetByPhone(c.Request.Context(), phoneNumber)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		c.Error(err)
+		return
+	}
+	if err != nil || user.PhoneVerifiedAt != nil {
+		c.JSON(http.StatusAccepted, gin.H{"message": "код отправлен"})
+		return
+	}
+
+	err = h.verifier.RequestCode(c.Request.Context(), user.Phone, verificationDB.PurposePhoneVerification, "Код подтверждения Car Social: %s")
+	if err != nil {
+		// Ограничение частоты кодов только логируем: ответ 429 выдавал бы,
+		// что номер зарегистрирован и не подтвержден
+		if !errors.Is(err, verification.ErrTooManyRequests) {
+			c.Error(err)
+			return
+		}
+		h.logger.WarnContext(c.Request.Context(), "phone verification code throttled", "target_user_id", user.ID, "ip", c.ClientIP(), "error", err)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "код отправлен"})
+}
+
+// VerifyPhone godoc
+// @Summary Подтверждение телефона
+// @Tags auth
+// @Description Подтверждение номера телефона одноразовым SMS-кодом
+// @Accept  json
+// @Produce  json
+// @Param input body PhoneVerifyRequest true "Номер телефона и код"
+// @Success 200 {object} Response "номер подтвержден"
+// @Failure 400 {object} response.ErrorResponse "неверный или истекший код"
+// @Failure 429 {object} response.ErrorResponse "превышено число попыток"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/phone/verify [post]
+func (h *Handler) verifyPhone(c *gin.Context) {
+	var req PhoneVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Для незарегистрированного номера отвечаем так же, как на неверный код
+	user, err := h.userRepo.GetByPhone(c.Request.Context(), phoneNumber)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			c.Error(verification.ErrInvalidCode)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if err := h.verifier.VerifyCode(c.Request.Context(), user.Phone, verificationDB.PurposePhoneVerification, req.Code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userRepo.MarkPhoneVerified(c.Request.Context(), user.ID, time.Now()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "номер телефона подтвержден"})
+}
+
+// ForgotPassword godoc
+// @Summary Запрос кода сброса пароля
+// @Tags auth
+// @Description Отправка одноразового SMS-кода для сброса пароля. Ответ не раскрывает, зарегистрирован ли номер.
+// @Accept  json
+// @Produce  json
+// @Param input body ForgotPasswordRequest true "Номер телефона"
+// @Success 202 {object} Response "код отправлен"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/password/forgot [post]
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Отвечаем одинаково для любых номеров, чтобы не раскрывать наличие аккаунта
+	user, err := h.userRepo.GetByPhone(c.Request.Context(), phoneNumber)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		c.Error(err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusAccepted, gin.H{"message": "код отправлен"})
+		return
+	}
+
+	err = h.verifier.RequestCode(c.Request.Context(), user.Phone, verificationDB.PurposePasswordReset, "Код для сброса пароля Car Social: %s")
+	if err != nil {
+		// Ограничение частоты кодов только логируется: ответ 429 выдавал бы,
+		// что для номера есть аккаунт
+		if !errors.Is(err, verification.ErrTooManyRequests) {
+			c.Error(err)
+			return
+		}
+		h.logger.WarnContext(c.Request.Context(), "password reset code throttled", "target_user_id", user.ID, "ip", c.ClientIP(), "error", err)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "код отправлен"})
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Tags auth
+// @Description Установка нового пароля по SMS-коду. Все сессии пользователя завершаются.
+// @Accept  json
+// @Produce  json
+// @Param input body ResetPasswordRequest true "Номер телефона, код и новый пароль"
+// @Success 200 {object} Response "пароль изменен"
+// @Failure 400 {object} response.ErrorResponse "неверный или истекший код"
+// @Failure 429 {object} response.ErrorResponse "превышено число попыток или слишком частые попытки для номера"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /auth/password/reset [post]
+func (h *Handler) resetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Лимит проверяется до поиска пользователя, чтобы одинаково
+	// ограничивать зарегистрированные и свободные номера
+	retryAfter, err := h.resetGuard.Check(c.Request.Context(), phoneNumber)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if retryAfter > 0 {
+		c.Error(apperror.WithRetryAfter(errResetPasswordLocked, retryAfter))
+		return
+	}
+
+	// Для незарегистрированного номера отвечаем так же, как на неверный код
+	user, err := h.userRepo.GetByPhone(c.Request.Context(), phoneNumber)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			c.Error(verification.ErrInvalidCode)
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if err := h.verifier.VerifyCode(c.Request.Context(), user.Phone, verificationDB.PurposePasswordReset, req.Code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	passwordHash, err := h.passwords.Hash(req.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	user.PasswordHash = passwordHash
+	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Код пришел на номер пользователя, значит номер подтвержден
+	if user.PhoneVerifiedAt == nil {
+		if err := h.userRepo.MarkPhoneVerified(c.Request.Context(), user.ID, time.Now()); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	// Завершаем сессии на всех устройствах
+	if err := h.authRepo.DeleteUserSessions(c.Request.Context(), user.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "password reset", "target_user_id", user.ID, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"message": "пароль изменен"})
+}
@@ -0,0 +1,54 @@
+package car
+
+import (
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+)
+
+// CreateRequest представляет структуру запроса на добавление машины в гараж
+type CreateRequest struct {
+	Make       string `json:"make" binding:"required,max=100" example:"Toyota"`
+	Model      string `json:"model" binding:"required,max=100" example:"Supra"`
+	Generation string `json:"generation,omitempty" binding:"max=100" example:"A80"`
+	Year       int    `json:"year" binding:"required,min=1886,max=2100" example:"1998"`
+	VIN        string `json:"vin,omitempty" binding:"omitempty,len=17,alphanum" example:"JT2JA82J3W0012345"`
+	Color      string `json:"color,omitempty" binding:"max=50" example:"white"`
+	Engine     string `json:"engine,omitempty" binding:"max=100" example:"2JZ-GTE"`
+	Mileage    int    `json:"mileage,omitempty" binding:"min=0" example:"120000"`
+	IsPrimary  bool   `json:"is_primary,omitempty" example:"true"`
+	// PhotoIDs - изображения, загруженные владельцем через /media, в порядке показа
+	PhotoIDs []int `json:"photo_ids,omitempty" binding:"max=20,dive,min=1" example:"5,6"`
+}
+
+// UpdateRequest представляет структуру запроса на обновление машины;
+// пустой photo_ids убирает все фотографии
+type UpdateRequest struct {
+	Make       string `json:"make,omitempty" binding:"max=100" example:"Toyota"`
+	Model      string `json:"model,omitempty" binding:"max=100" example:"Supra"`
+	Generation string `json:"generation,omitempty" binding:"max=100" example:"A80"`
+	Year       *int   `json:"year,omitempty" binding:"omitempty,min=1886,max=2100" example:"1998"`
+	VIN        string `json:"vin,omitempty" binding:"omitempty,len=17,alphanum" example:"JT2JA82J3W0012345"`
+	Color      string `json:"color,omitempty" binding:"max=50" example:"black"`
+	Engine     string `json:"engine,omitempty" binding:"max=100" example:"2JZ-GTE"`
+	Mileage    *int   `json:"mileage,omitempty" binding:"omitempty,min=0" example:"125000"`
+	IsPrimary  *bool  `json:"is_primary,omitempty" example:"true"`
+	PhotoIDs   []int  `json:"photo_ids,omitempty" binding:"max=20,dive,min=1" example:"5,6"`
+}
+
+// Response представляет структуру ответа с данными машины
+type Response struct {
+	ID         int               `json:"id" example:"1"`
+	UserID     int               `json:"user_id" example:"1"`
+	Make       string            `json:"make" example:"Toyota"`
+	Model      string            `json:"model" example:"Supra"`
+	Generation string            `json:"generation" example:"A80"`
+	Year       int               `json:"year" example:"1998"`
+	VIN        string            `json:"vin" example:"JT2JA82J3W0012345"`
+	Color      string            `json:"color" example:"white"`
+	Engine     string            `json:"engine" example:"2JZ-GTE"`
+	Mileage    int               `json:"mileage" example:"120000"`
+	IsPrimary  bool              `json:"is_primary" example:"true"`
+	Photos     []media.Response  `json:"photos"`
+	Reactions  reaction.Response `json:"reactions"`
+	CreatedAt  string            `json:"created_at" example:"2024-03-20 15:04:05"`
+}
@@ -0,0 +1,361 @@
+package car
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	carDB "github.com/NikitaBelov-mobile/car-social/internal/database/car"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+	reactionDB "github.com/NikitaBelov-mobile/car-social/internal/database/reaction"
+	userDB "github.com/NikitaBelov-mobile/car-social/internal/database/user"
+	mediaService "github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	mediaHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	reactionHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	userRepo     userDB.UserRepository
+	carRepo      carDB.CarRepository
+	mediaRepo    mediaDB.MediaRepository
+	media        *mediaService.Service
+	reactionRepo reactionDB.ReactionRepository
+}
+
+func NewHandler(
+	userRepo userDB.UserRepository,
+	carRepo carDB.CarRepository,
+	mediaRepo mediaDB.MediaRepository,
+	media *mediaService.Service,
+	reactionRepo reactionDB.ReactionRepository,
+) *Handler {
+	return &Handler{
+		userRepo:     userRepo,
+		carRepo:      carRepo,
+		mediaRepo:    mediaRepo,
+		media:        media,
+		reactionRepo: reactionRepo,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	cars := router.Group("/users/:id/cars")
+	{
+		cars.GET("", h.list)                                         // Гараж пользователя
+		cars.GET("/:carId", h.getByID)                               // Получение машины
+		cars.POST("", middleware.OwnerOnly("id"), h.create)          // Добавление машины
+		cars.PUT("/:carId", middleware.OwnerOnly("id"), h.update)    // Обновление машины
+		cars.DELETE("/:carId", middleware.OwnerOnly("id"), h.delete) // Удаление машины
+	}
+}
+
+// Create godoc
+// @Summary Добавление машины
+// @Tags cars
+// @Description Добавление машины в гараж пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param input body CreateRequest true "Данные машины"
+// @Security BearerAuth
+// @Success 201 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "нет доступа к гаражу"
+// @Failure 409 {object} response.ErrorResponse "основная машина изменена параллельным запросом"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/cars [post]
+func (h *Handler) create(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	car := &carDB.Car{
+		UserID:     userID,
+		Make:       req.Make,
+		Model:      req.Model,
+		Generation: req.Generation,
+		Year:       req.Year,
+		VIN:        strings.ToUpper(req.VIN),
+		Color:      req.Color,
+		Engine:     req.Engine,
+		Mileage:    req.Mileage,
+		IsPrimary:  req.IsPrimary,
+	}
+
+	if err := h.media.CheckOwned(c.Request.Context(), userID, req.PhotoIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.carRepo.Create(c.Request.Context(), car, req.PhotoIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, car)
+}
+
+// List godoc
+// @Summary Гараж пользователя
+// @Tags cars
+// @Description Получение списка машин пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Security BearerAuth
+// @Success 200 {array} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/cars [get]
+func (h *Handler) list(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	if _, err := h.userRepo.GetByID(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	cars, err := h.carRepo.ListByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ids := make([]int, 0, len(cars))
+	for _, car := range cars {
+		ids = append(ids, car.ID)
+	}
+
+	photos, err := h.mediaRepo.ListCarPhotos(c.Request.Context(), ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetCar, ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := make([]Response, 0, len(cars))
+	for _, car := range cars {
+		resp = append(resp, h.toResponse(car, photos[car.ID], reactions[car.ID]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetByID godoc
+// @Summary Получение машины
+// @Tags cars
+// @Description Получение информации о машине из гаража пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param carId path int true "ID машины"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "машина не найдена"
+// @Router /users/{id}/cars/{carId} [get]
+func (h *Handler) getByID(c *gin.Context) {
+	car, ok := h.getUserCar(c)
+	if !ok {
+		return
+	}
+
+	h.respond(c, http.StatusOK, car)
+}
+
+// Update godoc
+// @Summary Обновление машины
+// @Tags cars
+// @Description Обновление данных машины в гараже пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param carId path int true "ID машины"
+// @Param input body UpdateRequest true "Данные для обновления"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "нет доступа к гаражу"
+// @Failure 404 {object} response.ErrorResponse "машина не найдена"
+// @Failure 409 {object} response.ErrorResponse "основная машина изменена параллельным запросом"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/cars/{carId} [put]
+func (h *Handler) update(c *gin.Context) {
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	car, ok := h.getUserCar(c)
+	if !ok {
+		return
+	}
+
+	// Обновляем только переданные поля
+	if req.Make != "" {
+		car.Make = req.Make
+	}
+	if req.Model != "" {
+		car.Model = req.Model
+	}
+	if req.Generation != "" {
+		car.Generation = req.Generation
+	}
+	if req.Year != nil {
+		car.Year = *req.Year
+	}
+	if req.VIN != "" {
+		car.VIN = strings.ToUpper(req.VIN)
+	}
+	if req.Color != "" {
+		car.Color = req.Color
+	}
+	if req.Engine != "" {
+		car.Engine = req.Engine
+	}
+	if req.Mileage != nil {
+		car.Mileage = *req.Mileage
+	}
+	if req.IsPrimary != nil {
+		car.IsPrimary = *req.IsPrimary
+	}
+
+	if err := h.media.CheckOwned(c.Request.Context(), car.UserID, req.PhotoIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// photo_ids не передан - фотографии не меняются
+	if err := h.carRepo.Update(c.Request.Context(), car, req.PhotoIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, car)
+}
+
+// Delete godoc
+// @Summary Удаление машины
+// @Tags cars
+// @Description Удаление машины из гаража пользователя
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param carId path int true "ID машины"
+// @Security BearerAuth
+// @Success 204 "машина удалена"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "нет доступа к гаражу"
+// @Failure 404 {object} response.ErrorResponse "машина не найдена"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/cars/{carId} [delete]
+func (h *Handler) delete(c *gin.Context) {
+	car, ok := h.getUserCar(c)
+	if !ok {
+		return
+	}
+
+	if err := h.carRepo.Delete(c.Request.Context(), car.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getUserCar загружает машину из пути и проверяет, что она принадлежит
+// пользователю из пути. При ошибке ответ уже записан в контекст.
+func (h *Handler) getUserCar(c *gin.Context) (*carDB.Car, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return nil, false
+	}
+
+	carID, err := strconv.Atoi(c.Param("carId"))
+	if err != nil {
+		c.Error(apperror.InvalidInput("invalid_car_id", "invalid car id format"))
+		return nil, false
+	}
+
+	car, err := h.carRepo.GetByID(c.Request.Context(), carID)
+	if err != nil {
+		c.Error(err)
+		return nil, false
+	}
+
+	if car.UserID != userID {
+		c.Error(carDB.ErrCarNotFound)
+		return nil, false
+	}
+
+	return car, true
+}
+
+// respond отвечает машиной вместе с ее фотографиями и реакциями
+func (h *Handler) respond(c *gin.Context, status int, car *carDB.Car) {
+	photos, err := h.mediaRepo.ListCarPhotos(c.Request.Context(), []int{car.ID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetCar, []int{car.ID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(status, h.toResponse(car, photos[car.ID], reactions[car.ID]))
+}
+
+func (h *Handler) toResponse(car *carDB.Car, photos []*mediaDB.Media, reactions *reactionDB.Summary) Response {
+	return Response{
+		ID:         car.ID,
+		UserID:     car.UserID,
+		Make:       car.Make,
+		Model:      car.Model,
+		Generation: car.Generation,
+		Year:       car.Year,
+		VIN:        car.VIN,
+		Color:      car.Color,
+		Engine:     car.Engine,
+		Mileage:    car.Mileage,
+		IsPrimary:  car.IsPrimary,
+		Photos:     mediaHandler.ToResponses(h.media, photos),
+		Reactions:  reactionHandler.ToResponse(reactions),
+		CreatedAt:  car.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,34 @@
+package comment
+
+import "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+
+// CreateRequest представляет структуру запроса на создание комментария.
+// Ответ на ответ прикрепляется к комментарию верхнего уровня той же ветки.
+type CreateRequest struct {
+	Body     string `json:"body" binding:"required,max=2000" example:"Какое масло заливал?"`
+	ParentID *int   `json:"parent_id,omitempty" binding:"omitempty,min=1" example:"1"`
+}
+
+// UpdateRequest представляет структуру запроса на редактирование комментария
+type UpdateRequest struct {
+	Body string `json:"body" binding:"required,max=2000" example:"Какое масло и фильтр ставил?"`
+}
+
+// Response представляет структуру ответа с данными комментария
+type Response struct {
+	ID         int               `json:"id" example:"2"`
+	PostID     int               `json:"post_id" example:"1"`
+	UserID     int               `json:"user_id" example:"1"`
+	ParentID   *int              `json:"parent_id,omitempty" example:"1"`
+	Body       string            `json:"body" example:"Какое масло заливал?"`
+	ReplyCount int               `json:"reply_count" example:"0"`
+	Reactions  reaction.Response `json:"reactions"`
+	CreatedAt  string            `json:"created_at" example:"2024-03-20 15:04:05"`
+	UpdatedAt  string            `json:"updated_at" example:"2024-03-20 15:04:05"`
+}
+
+// ListResponse представляет страницу комментариев
+type ListResponse struct {
+	Items      []Response `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty" example:"MjAyNC0wMy0yMFQxNTowNDowNVp8MQ"`
+}
@@ -0,0 +1,360 @@
+package comment
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	commentDB "github.com/NikitaBelov-mobile/car-social/internal/database/comment"
+	postDB "github.com/NikitaBelov-mobile/car-social/internal/database/post"
+	reactionDB "github.com/NikitaBelov-mobile/car-social/internal/database/reaction"
+	reactionHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/pagination"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	postRepo     postDB.PostRepository
+	commentRepo  commentDB.CommentRepository
+	reactionRepo reactionDB.ReactionRepository
+}
+
+func NewHandler(
+	postRepo postDB.PostRepository,
+	commentRepo commentDB.CommentRepository,
+	reactionRepo reactionDB.ReactionRepository,
+) *Handler {
+	return &Handler{
+		postRepo:     postRepo,
+		commentRepo:  commentRepo,
+		reactionRepo: reactionRepo,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	router.POST("/posts/:id/comments", h.create) // Комментарий к посту
+	router.GET("/posts/:id/comments", h.list)    // Комментарии поста
+	router.PUT("/comments/:id", h.update)        // Редактирование комментария
+	router.DELETE("/comments/:id", h.delete)     // Удаление комментария
+}
+
+// Create godoc
+// @Summary Комментарий к посту
+// @Tags comments
+// @Description Добавление комментария к посту или ответа на комментарий через parent_id. Ответы поддерживают один уровень вложенности: ответ на ответ прикрепляется к комментарию верхнего уровня.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID поста"
+// @Param input body CreateRequest true "Данные комментария"
+// @Security BearerAuth
+// @Success 201 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных или родительский комментарий не найден"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пост не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id}/comments [post]
+func (h *Handler) create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	comment := &commentDB.Comment{
+		PostID: postID,
+		UserID: userID,
+		Body:   req.Body,
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.commentRepo.GetByID(c.Request.Context(), *req.ParentID)
+		if err != nil {
+			if errors.Is(err, apperror.ErrNotFound) {
+				err = commentDB.ErrParentNotFound
+			}
+			c.Error(err)
+			return
+		}
+
+		if parent.PostID != postID {
+			c.Error(commentDB.ErrParentNotFound)
+			return
+		}
+
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	// Существование поста и родителя проверяется в транзакции вместе
+	// с обновлением счетчиков
+	if err := h.commentRepo.Create(c.Request.Context(), comment); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, comment)
+}
+
+// List godoc
+// @Summary Комментарии поста
+// @Tags comments
+// @Description Комментарии верхнего уровня в хронологическом порядке с cursor-пагинацией. С parent_id возвращаются ответы на указанный комментарий.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID поста"
+// @Param parent_id query int false "ID комментария, ответы на который нужно получить"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пост или комментарий не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id}/comments [get]
+func (h *Handler) list(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if filter.ParentID != nil {
+		parent, err := h.commentRepo.GetByID(c.Request.Context(), *filter.ParentID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		if parent.PostID != postID {
+			c.Error(commentDB.ErrCommentNotFound)
+			return
+		}
+	} else if _, err := h.postRepo.GetByID(c.Request.Context(), postID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comments, err := h.commentRepo.ListByPost(c.Request.Context(), postID, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ids := make([]int, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetComment, ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toListResponse(comments, reactions, filter.Limit))
+}
+
+// Update godoc
+// @Summary Редактирование комментария
+// @Tags comments
+// @Description Редактирование комментария его автором
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID комментария"
+// @Param input body UpdateRequest true "Данные для обновления"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "комментарий принадлежит другому пользователю"
+// @Failure 404 {object} response.ErrorResponse "комментарий не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /comments/{id} [put]
+func (h *Handler) update(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if comment.UserID != userID {
+		c.Error(response.ErrAccessDenied)
+		return
+	}
+
+	comment.Body = req.Body
+	if err := h.commentRepo.Update(c.Request.Context(), comment); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, comment)
+}
+
+// Delete godoc
+// @Summary Удаление комментария
+// @Tags comments
+// @Description Удаление комментария его автором или автором поста. Вместе с комментарием верхнего уровня удаляются ответы на него.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID комментария"
+// @Security BearerAuth
+// @Success 204 "комментарий удален"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "нет прав на удаление комментария"
+// @Failure 404 {object} response.ErrorResponse "комментарий не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /comments/{id} [delete]
+func (h *Handler) delete(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	comment, err := h.commentRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if comment.UserID != userID {
+		post, err := h.postRepo.GetByID(c.Request.Context(), comment.PostID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		if post.UserID != userID {
+			c.Error(response.ErrAccessDenied)
+			return
+		}
+	}
+
+	if err := h.commentRepo.Delete(c.Request.Context(), comment); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseListFilter(c *gin.Context) (commentDB.ListFilter, error) {
+	limit, err := pagination.ParseLimit(c.Query("limit"))
+	if err != nil {
+		return commentDB.ListFilter{}, err
+	}
+
+	filter := commentDB.ListFilter{Limit: limit}
+
+	if value := c.Query("parent_id"); value != "" {
+		parentID, err := strconv.Atoi(value)
+		if err != nil || parentID <= 0 {
+			return commentDB.ListFilter{}, response.ErrInvalidID
+		}
+		filter.ParentID = &parentID
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		createdAt, id, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return commentDB.ListFilter{}, err
+		}
+		filter.Cursor = &commentDB.Cursor{CreatedAt: createdAt, ID: id}
+	}
+
+	return filter, nil
+}
+
+// respond отвечает комментарием вместе с реакциями на него
+func (h *Handler) respond(c *gin.Context, status int, comment *commentDB.Comment) {
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetComment, []int{comment.ID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(status, toResponse(comment, reactions[comment.ID]))
+}
+
+func toListResponse(comments []*commentDB.Comment, reactions map[int]*reactionDB.Summary, limit int) ListResponse {
+	resp := ListResponse{Items: make([]Response, 0, len(comments))}
+	for _, comment := range comments {
+		resp.Items = append(resp.Items, toResponse(comment, reactions[comment.ID]))
+	}
+
+	// Полная страница означает, что дальше могут быть еще комментарии
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		resp.NextCursor = pagination.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return resp
+}
+
+func toResponse(comment *commentDB.Comment, reactions *reactionDB.Summary) Response {
+	return Response{
+		ID:         comment.ID,
+		PostID:     comment.PostID,
+		UserID:     comment.UserID,
+		ParentID:   comment.ParentID,
+		Body:       comment.Body,
+		ReplyCount: comment.ReplyCount,
+		Reactions:  reactionHandler.ToResponse(reactions),
+		CreatedAt:  comment.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:  comment.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,14 @@
+package follow
+
+// UserResponse представляет пользователя в списке подписчиков или подписок
+type UserResponse struct {
+	UserID     int    `json:"user_id" example:"2"`
+	FollowedAt string `json:"followed_at" example:"2024-03-20 15:04:05"`
+}
+
+// ListResponse представляет страницу подписчиков или подписок
+type ListResponse struct {
+	Items      []UserResponse `json:"items"`
+	Total      int            `json:"total" example:"42"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"MjAyNC0wMy0yMFQxNTowNDowNVp8Mg"`
+}
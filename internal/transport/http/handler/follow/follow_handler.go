@@ -0,0 +1,233 @@
+package follow
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	followDB "github.com/NikitaBelov-mobile/car-social/internal/database/follow"
+	userDB "github.com/NikitaBelov-mobile/car-social/internal/database/user"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/pagination"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	userRepo   userDB.UserRepository
+	followRepo followDB.FollowRepository
+}
+
+func NewHandler(userRepo userDB.UserRepository, followRepo followDB.FollowRepository) *Handler {
+	return &Handler{
+		userRepo:   userRepo,
+		followRepo: followRepo,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	users := router.Group("/users/:id")
+	{
+		users.POST("/follow", h.follow)          // Подписка на пользователя
+		users.DELETE("/follow", h.unfollow)      // Отписка от пользователя
+		users.GET("/followers", h.listFollowers) // Подписчики пользователя
+		users.GET("/following", h.listFollowing) // Подписки пользователя
+	}
+}
+
+// Follow godoc
+// @Summary Подписка на пользователя
+// @Tags follows
+// @Description Подписка текущего пользователя на пользователя с указанным ID. Повторная подписка не является ошибкой.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Security BearerAuth
+// @Success 204 "подписка оформлена"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID или подписка на себя"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/follow [post]
+func (h *Handler) follow(c *gin.Context) {
+	followerID, followeeID, ok := h.parseFollowPair(c)
+	if !ok {
+		return
+	}
+
+	if err := h.followRepo.Follow(c.Request.Context(), followerID, followeeID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Unfollow godoc
+// @Summary Отписка от пользователя
+// @Tags follows
+// @Description Отписка текущего пользователя от пользователя с указанным ID. Отписка без подписки не является ошибкой.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Security BearerAuth
+// @Success 204 "подписка отменена"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/follow [delete]
+func (h *Handler) unfollow(c *gin.Context) {
+	followerID, followeeID, ok := h.parseFollowPair(c)
+	if !ok {
+		return
+	}
+
+	if err := h.followRepo.Unfollow(c.Request.Context(), followerID, followeeID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListFollowers godoc
+// @Summary Подписчики пользователя
+// @Tags follows
+// @Description Получение подписчиков пользователя с общим количеством и cursor-пагинацией
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/followers [get]
+func (h *Handler) listFollowers(c *gin.Context) {
+	h.list(c, true)
+}
+
+// ListFollowing godoc
+// @Summary Подписки пользователя
+// @Tags follows
+// @Description Получение пользователей, на которых подписан пользователь, с общим количеством и cursor-пагинацией
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/following [get]
+func (h *Handler) listFollowing(c *gin.Context) {
+	h.list(c, false)
+}
+
+func (h *Handler) list(c *gin.Context, followers bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(c.Query("limit"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var cursor *followDB.Cursor
+	if value := c.Query("cursor"); value != "" {
+		createdAt, id, err := pagination.DecodeCursor(value)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		cursor = &followDB.Cursor{CreatedAt: createdAt, UserID: id}
+	}
+
+	if _, err := h.userRepo.GetByID(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	counts, err := h.followRepo.GetCounts(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var follows []*followDB.Follow
+	resp := ListResponse{}
+	if followers {
+		follows, err = h.followRepo.ListFollowers(c.Request.Context(), userID, cursor, limit)
+		resp.Total = counts.Followers
+	} else {
+		follows, err = h.followRepo.ListFollowing(c.Request.Context(), userID, cursor, limit)
+		resp.Total = counts.Following
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp.Items = make([]UserResponse, 0, len(follows))
+	for _, follow := range follows {
+		otherID := follow.FolloweeID
+		if followers {
+			otherID = follow.FollowerID
+		}
+		resp.Items = append(resp.Items, UserResponse{
+			UserID:     otherID,
+			FollowedAt: follow.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	// Полная страница означает, что дальше могут быть еще записи
+	if len(follows) == limit {
+		last := resp.Items[len(resp.Items)-1]
+		resp.NextCursor = pagination.EncodeCursor(follows[len(follows)-1].CreatedAt, last.UserID)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseFollowPair определяет подписчика из токена и целевого пользователя
+// из пути. При ошибке ответ уже записан в контекст.
+func (h *Handler) parseFollowPair(c *gin.Context) (int, int, bool) {
+	followerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return 0, 0, false
+	}
+
+	followeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return 0, 0, false
+	}
+
+	if followerID == followeeID {
+		c.Error(apperror.InvalidInput("cannot_follow_yourself", "cannot follow yourself"))
+		return 0, 0, false
+	}
+
+	if _, err := h.userRepo.GetByID(c.Request.Context(), followeeID); err != nil {
+		c.Error(err)
+		return 0, 0, false
+	}
+
+	return followerID, followeeID, true
+}
@@ -0,0 +1,9 @@
+package health
+
+// Response представляет результат проверки состояния сервиса
+type Response struct {
+	Status string `json:"status" example:"ok"`
+	// Checks содержит результат каждой проверки готовности: "ok" или
+	// описание проблемы
+	Checks map[string]string `json:"checks,omitempty"`
+}
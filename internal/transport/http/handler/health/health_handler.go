@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// Pinger проверяет доступность базы данных
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchemaVersioner сообщает текущую и ожидаемую версии схемы
+type SchemaVersioner interface {
+	Version(ctx context.Context) (version uint, dirty bool, err error)
+	Latest() uint
+}
+
+type Handler struct {
+	db      Pinger
+	schema  SchemaVersioner
+	timeout time.Duration
+	logger  *slog.Logger
+
+	shuttingDown atomic.Bool
+}
+
+// NewHandler создает обработчик проверок состояния. timeout ограничивает
+// время проверок базы в /readyz.
+func NewHandler(db Pinger, schema SchemaVersioner, timeout time.Duration, logger *slog.Logger) *Handler {
+	return &Handler{
+		db:      db,
+		schema:  schema,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	router.GET("/healthz", h.healthz) // Процесс жив
+	router.GET("/readyz", h.readyz)   // Сервис готов принимать трафик
+}
+
+// SetShuttingDown переводит /readyz в неготовое состояние, чтобы балансировщик
+// перестал направлять трафик до закрытия соединений с базой
+func (h *Handler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Healthz godoc
+// @Summary Проверка жизнеспособности
+// @Tags health
+// @Description Отвечает 200, пока процесс работает. Зависимости не проверяются.
+// @Produce  json
+// @Success 200 {object} Response "процесс работает"
+// @Router /healthz [get]
+func (h *Handler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{Status: statusOK})
+}
+
+// Readyz godoc
+// @Summary Проверка готовности
+// @Tags health
+// @Description Проверяет доступность базы данных и версию схемы. Во время остановки сервера отвечает 503.
+// @Produce  json
+// @Success 200 {object} Response "сервис готов"
+// @Failure 503 {object} Response "сервис не готов"
+// @Router /readyz [get]
+func (h *Handler) readyz(c *gin.Context) {
+	if h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Status: statusUnavailable,
+			Checks: map[string]string{"server": "shutting down"},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	checks := map[string]string{
+		"database":   statusOK,
+		"migrations": statusOK,
+	}
+	ready := true
+
+	// Подробности ошибки подключения наружу не отдаем
+	if err := h.db.PingContext(ctx); err != nil {
+		checks["database"] = "unreachable"
+		checks["migrations"] = "skipped"
+		ready = false
+	} else if state, err := h.checkSchema(ctx); err != nil {
+		// Ошибку чтения версии только логируем: в ней могут быть детали базы
+		h.logger.ErrorContext(c.Request.Context(), "readiness check failed to read schema version", "error", err)
+		checks["migrations"] = statusUnavailable
+		ready = false
+	} else if state != statusOK {
+		checks["migrations"] = state
+		ready = false
+	}
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, Response{Status: statusUnavailable, Checks: checks})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Status: statusOK, Checks: checks})
+}
+
+// checkSchema проверяет, что все миграции приложения применены, и
+// возвращает statusOK или описание расхождения версий. Более новая версия
+// схемы допустима: ее применила более новая версия приложения.
+func (h *Handler) checkSchema(ctx context.Context) (string, error) {
+	version, dirty, err := h.schema.Version(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if dirty {
+		return fmt.Sprintf("schema is dirty at version %d", version), nil
+	}
+
+	if latest := h.schema.Latest(); version < latest {
+		return fmt.Sprintf("schema version %d, expected %d", version, latest), nil
+	}
+
+	return statusOK, nil
+}
@@ -0,0 +1,22 @@
+package media
+
+// Response представляет загруженное изображение
+type Response struct {
+	ID          int                 `json:"id" example:"1"`
+	URL         string              `json:"url" example:"/uploads/1/3f2a9c0e5b7d4e18a6c2f0b9d8e7a615/original.jpg"`
+	ContentType string              `json:"content_type" example:"image/jpeg"`
+	Width       int                 `json:"width" example:"1920"`
+	Height      int                 `json:"height" example:"1080"`
+	Size        int64               `json:"size" example:"348211"`
+	Checksum    string              `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Thumbnails  []ThumbnailResponse `json:"thumbnails"`
+	CreatedAt   string              `json:"created_at" example:"2024-03-20 15:04:05"`
+}
+
+// ThumbnailResponse представляет миниатюру изображения
+type ThumbnailResponse struct {
+	Size   int    `json:"size" example:"480"`
+	URL    string `json:"url" example:"/uploads/1/3f2a9c0e5b7d4e18a6c2f0b9d8e7a615/480.jpg"`
+	Width  int    `json:"width" example:"480"`
+	Height int    `json:"height" example:"270"`
+}
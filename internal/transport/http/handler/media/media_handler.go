@@ -0,0 +1,257 @@
+package media
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+// formField - поле multipart-формы с файлом
+const formField = "file"
+
+// multipartOverhead - запас на заголовки частей multipart-формы сверх
+// максимального размера файла
+const multipartOverhead = 64 << 10
+
+var errFileRequired = apperror.InvalidInput("file_required", "multipart form field \"file\" is required")
+
+// URLResolver строит адрес файла по его ключу
+type URLResolver interface {
+	URL(key string) string
+}
+
+type Handler struct {
+	mediaRepo mediaDB.MediaRepository
+	media     *media.Service
+}
+
+func NewHandler(mediaRepo mediaDB.MediaRepository, media *media.Service) *Handler {
+	return &Handler{
+		mediaRepo: mediaRepo,
+		media:     media,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	media := router.Group("/media")
+	{
+		media.POST("", h.upload)       // Загрузка изображения
+		media.GET("/:id", h.getByID)   // Получение изображения
+		media.DELETE("/:id", h.delete) // Удаление изображения
+	}
+}
+
+// Upload godoc
+// @Summary Загрузка изображения
+// @Tags media
+// @Description Загрузка изображения JPEG, PNG или WebP в поле формы file. Тип определяется по содержимому файла. Метаданные (EXIF) удаляются, ориентация снимка применяется к изображению, строятся миниатюры. Полученный ID указывается в avatar_media_id профиля, media_ids поста или photo_ids машины.
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "Изображение"
+// @Security BearerAuth
+// @Success 201 {object} Response
+// @Failure 400 {object} response.ErrorResponse "файл не передан или поврежден"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 413 {object} response.ErrorResponse "файл или изображение слишком большие"
+// @Failure 415 {object} response.ErrorResponse "неподдерживаемый формат"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /media [post]
+func (h *Handler) upload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	// Форма читается потоком без временных файлов; тело запроса ограничено,
+	// чтобы клиент не мог передать больше, чем сервис согласится принять
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.media.MaxUploadSize())+multipartOverhead)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.Error(errFileRequired)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.Error(errFileRequired)
+			return
+		}
+		if err != nil {
+			c.Error(uploadError(err))
+			return
+		}
+
+		if part.FormName() != formField {
+			part.Close()
+			continue
+		}
+
+		// Ошибки чтения тела запроса отличаются от ошибок хранилища и базы:
+		// только первые означают, что клиент прислал неверную форму
+		body := &partReader{r: part}
+		uploaded, err := h.media.Upload(c.Request.Context(), userID, body)
+		part.Close()
+		if body.err != nil {
+			c.Error(uploadError(body.err))
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, ToResponse(h.media, uploaded))
+		return
+	}
+}
+
+// partReader запоминает ошибку чтения части формы
+type partReader struct {
+	r   io.Reader
+	err error
+}
+
+func (p *partReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if err != nil && err != io.EOF {
+		p.err = err
+	}
+	return n, err
+}
+
+// uploadError превращает ошибку чтения тела запроса в ответ клиенту
+func uploadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return media.ErrFileTooLarge
+	}
+
+	return apperror.InvalidInput("invalid_multipart", "invalid multipart form")
+}
+
+// GetByID godoc
+// @Summary Получение изображения
+// @Tags media
+// @Description Метаданные изображения и адреса файлов
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID изображения"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "изображение не найдено"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /media/{id} [get]
+func (h *Handler) getByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	m, err := h.mediaRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToResponse(h.media, m))
+}
+
+// Delete godoc
+// @Summary Удаление изображения
+// @Tags media
+// @Description Удаление изображения его владельцем. Изображение пропадает из профиля, постов и машин, к которым было привязано.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID изображения"
+// @Security BearerAuth
+// @Success 204 "изображение удалено"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "изображение загружено другим пользователем"
+// @Failure 404 {object} response.ErrorResponse "изображение не найдено"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /media/{id} [delete]
+func (h *Handler) delete(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	m, err := h.mediaRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if m.OwnerID != userID {
+		c.Error(response.ErrAccessDenied)
+		return
+	}
+
+	if err := h.media.Delete(c.Request.Context(), m); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ToResponse используется также обработчиками профилей, постов и машин
+func ToResponse(urls URLResolver, m *mediaDB.Media) Response {
+	resp := Response{
+		ID:          m.ID,
+		URL:         urls.URL(m.StorageKey),
+		ContentType: m.ContentType,
+		Width:       m.Width,
+		Height:      m.Height,
+		Size:        m.Size,
+		Checksum:    m.Checksum,
+		Thumbnails:  make([]ThumbnailResponse, 0, len(m.Thumbnails)),
+		CreatedAt:   m.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	for _, thumbnail := range m.Thumbnails {
+		resp.Thumbnails = append(resp.Thumbnails, ThumbnailResponse{
+			Size:   thumbnail.Size,
+			URL:    urls.URL(thumbnail.StorageKey),
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+		})
+	}
+
+	return resp
+}
+
+// ToResponses преобразует список изображений; nil превращается в пустой
+// список, чтобы в JSON было [], а не null
+func ToResponses(urls URLResolver, media []*mediaDB.Media) []Response {
+	resp := make([]Response, 0, len(media))
+	for _, m := range media {
+		resp = append(resp, ToResponse(urls, m))
+	}
+	return resp
+}
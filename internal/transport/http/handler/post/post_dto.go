@@ -0,0 +1,49 @@
+package post
+
+import (
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+)
+
+// CreateRequest представляет структуру запроса на создание поста
+type CreateRequest struct {
+	Body  string `json:"body" binding:"required,max=5000" example:"Поменял масло, едет как новая"`
+	CarID *int   `json:"car_id,omitempty" example:"1"`
+	// MediaIDs - изображения, загруженные автором через /media, в порядке показа
+	MediaIDs []int `json:"media_ids,omitempty" binding:"max=10,dive,min=1" example:"3,4"`
+}
+
+// UpdateRequest представляет структуру запроса на редактирование поста.
+// car_id = 0 отвязывает машину от поста, пустой media_ids убирает все
+// изображения.
+type UpdateRequest struct {
+	Body     string `json:"body,omitempty" binding:"max=5000" example:"Поменял масло и фильтры"`
+	CarID    *int   `json:"car_id,omitempty" example:"1"`
+	MediaIDs []int  `json:"media_ids,omitempty" binding:"max=10,dive,min=1" example:"3,4"`
+}
+
+// CarResponse представляет краткие данные машины, привязанной к посту
+type CarResponse struct {
+	ID    int    `json:"id" example:"1"`
+	Make  string `json:"make" example:"Toyota"`
+	Model string `json:"model" example:"Supra"`
+}
+
+// Response представляет структуру ответа с данными поста
+type Response struct {
+	ID           int               `json:"id" example:"1"`
+	UserID       int               `json:"user_id" example:"1"`
+	Body         string            `json:"body" example:"Поменял масло, едет как новая"`
+	Car          *CarResponse      `json:"car,omitempty"`
+	Media        []media.Response  `json:"media"`
+	CommentCount int               `json:"comment_count" example:"12"`
+	Reactions    reaction.Response `json:"reactions"`
+	CreatedAt    string            `json:"created_at" example:"2024-03-20 15:04:05"`
+	UpdatedAt    string            `json:"updated_at" example:"2024-03-20 15:04:05"`
+}
+
+// ListResponse представляет страницу постов
+type ListResponse struct {
+	Items      []Response `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty" example:"MjAyNC0wMy0yMFQxNTowNDowNVp8MQ"`
+}
@@ -0,0 +1,497 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	carDB "github.com/NikitaBelov-mobile/car-social/internal/database/car"
+	feedDB "github.com/NikitaBelov-mobile/car-social/internal/database/feed"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+	postDB "github.com/NikitaBelov-mobile/car-social/internal/database/post"
+	reactionDB "github.com/NikitaBelov-mobile/car-social/internal/database/reaction"
+	userDB "github.com/NikitaBelov-mobile/car-social/internal/database/user"
+	mediaService "github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	mediaHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	reactionHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/reaction"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/pagination"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	userRepo     userDB.UserRepository
+	carRepo      carDB.CarRepository
+	postRepo     postDB.PostRepository
+	feedRepo     feedDB.FeedRepository
+	mediaRepo    mediaDB.MediaRepository
+	media        *mediaService.Service
+	reactionRepo reactionDB.ReactionRepository
+}
+
+func NewHandler(
+	userRepo userDB.UserRepository,
+	carRepo carDB.CarRepository,
+	postRepo postDB.PostRepository,
+	feedRepo feedDB.FeedRepository,
+	mediaRepo mediaDB.MediaRepository,
+	media *mediaService.Service,
+	reactionRepo reactionDB.ReactionRepository,
+) *Handler {
+	return &Handler{
+		userRepo:     userRepo,
+		carRepo:      carRepo,
+		postRepo:     postRepo,
+		feedRepo:     feedRepo,
+		mediaRepo:    mediaRepo,
+		media:        media,
+		reactionRepo: reactionRepo,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	posts := router.Group("/posts")
+	{
+		posts.POST("", h.create)       // Создание поста
+		posts.GET("/:id", h.getByID)   // Получение поста
+		posts.PUT("/:id", h.update)    // Редактирование поста
+		posts.DELETE("/:id", h.delete) // Удаление поста
+	}
+
+	router.GET("/users/:id/posts", h.listByAuthor) // Посты пользователя
+	router.GET("/feed", h.feed)                    // Домашняя лента
+}
+
+// Create godoc
+// @Summary Создание поста
+// @Tags posts
+// @Description Публикация поста от имени текущего пользователя с необязательной привязкой к машине из его гаража и изображениями, загруженными через /media
+// @Accept  json
+// @Produce  json
+// @Param input body CreateRequest true "Данные поста"
+// @Security BearerAuth
+// @Success 201 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts [post]
+func (h *Handler) create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	var req CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	post := &postDB.Post{
+		UserID: userID,
+		Body:   req.Body,
+	}
+
+	if req.CarID != nil {
+		if !h.attachCar(c, post, *req.CarID) {
+			return
+		}
+	}
+
+	if err := h.media.CheckOwned(c.Request.Context(), userID, req.MediaIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.postRepo.Create(c.Request.Context(), post); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(req.MediaIDs) > 0 {
+		if err := h.mediaRepo.SetPostMedia(c.Request.Context(), post.ID, req.MediaIDs); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	if err := h.feedRepo.PostPublished(c.Request.Context(), post); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, post)
+}
+
+// GetByID godoc
+// @Summary Получение поста
+// @Tags posts
+// @Description Получение поста по ID
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID поста"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пост не найден"
+// @Router /posts/{id} [get]
+func (h *Handler) getByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	post, err := h.postRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, post)
+}
+
+// Update godoc
+// @Summary Редактирование поста
+// @Tags posts
+// @Description Редактирование поста его автором
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID поста"
+// @Param input body UpdateRequest true "Данные для обновления"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "пост принадлежит другому пользователю"
+// @Failure 404 {object} response.ErrorResponse "пост не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id} [put]
+func (h *Handler) update(c *gin.Context) {
+	var req UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	post, ok := h.getOwnPost(c)
+	if !ok {
+		return
+	}
+
+	// Обновляем только переданные поля
+	if req.Body != "" {
+		post.Body = req.Body
+	}
+
+	if req.CarID != nil {
+		if *req.CarID == 0 {
+			post.CarID = nil
+			post.CarMake, post.CarModel = "", ""
+		} else if !h.attachCar(c, post, *req.CarID) {
+			return
+		}
+	}
+
+	if err := h.media.CheckOwned(c.Request.Context(), post.UserID, req.MediaIDs); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.postRepo.Update(c.Request.Context(), post); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// media_ids не передан - изображения не меняются
+	if req.MediaIDs != nil {
+		if err := h.mediaRepo.SetPostMedia(c.Request.Context(), post.ID, req.MediaIDs); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	h.respond(c, http.StatusOK, post)
+}
+
+// Delete godoc
+// @Summary Удаление поста
+// @Tags posts
+// @Description Удаление поста его автором
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID поста"
+// @Security BearerAuth
+// @Success 204 "пост удален"
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "пост принадлежит другому пользователю"
+// @Failure 404 {object} response.ErrorResponse "пост не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id} [delete]
+func (h *Handler) delete(c *gin.Context) {
+	post, ok := h.getOwnPost(c)
+	if !ok {
+		return
+	}
+
+	if err := h.postRepo.Delete(c.Request.Context(), post.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.feedRepo.PostDeleted(c.Request.Context(), post); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListByAuthor godoc
+// @Summary Посты пользователя
+// @Tags posts
+// @Description Получение постов пользователя в обратном хронологическом порядке с cursor-пагинацией
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID пользователя"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param make query string false "Марка машины"
+// @Param model query string false "Модель машины"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /users/{id}/posts [get]
+func (h *Handler) listByAuthor(c *gin.Context) {
+	authorID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if _, err := h.userRepo.GetByID(c.Request.Context(), authorID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	posts, err := h.postRepo.ListByAuthor(c.Request.Context(), authorID, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondList(c, posts, filter.Limit)
+}
+
+// Feed godoc
+// @Summary Домашняя лента
+// @Tags posts
+// @Description Посты пользователей, на которых подписан текущий пользователь, и его собственные посты в обратном хронологическом порядке
+// @Accept  json
+// @Produce  json
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param make query string false "Марка машины"
+// @Param model query string false "Модель машины"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /feed [get]
+func (h *Handler) feed(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	posts, err := h.feedRepo.GetHomeFeed(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondList(c, posts, filter.Limit)
+}
+
+// getOwnPost загружает пост из пути и проверяет, что текущий пользователь
+// является его автором. При ошибке ответ уже записан в контекст.
+func (h *Handler) getOwnPost(c *gin.Context) (*postDB.Post, bool) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return nil, false
+	}
+
+	post, err := h.postRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return nil, false
+	}
+
+	if post.UserID != userID {
+		c.Error(response.ErrAccessDenied)
+		return nil, false
+	}
+
+	return post, true
+}
+
+// attachCar привязывает к посту машину, если она есть в гараже автора.
+// При ошибке ответ уже записан в контекст.
+func (h *Handler) attachCar(c *gin.Context, post *postDB.Post, carID int) bool {
+	car, err := h.carRepo.GetByID(c.Request.Context(), carID)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		c.Error(err)
+		return false
+	}
+
+	if err != nil || car.UserID != post.UserID {
+		c.Error(apperror.InvalidInput("car_not_in_garage", "car not found in author's garage"))
+		return false
+	}
+
+	post.CarID = &car.ID
+	post.CarMake = car.Make
+	post.CarModel = car.Model
+
+	return true
+}
+
+func parseListFilter(c *gin.Context) (postDB.ListFilter, error) {
+	limit, err := pagination.ParseLimit(c.Query("limit"))
+	if err != nil {
+		return postDB.ListFilter{}, err
+	}
+
+	filter := postDB.ListFilter{
+		Make:  c.Query("make"),
+		Model: c.Query("model"),
+		Limit: limit,
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		createdAt, id, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return postDB.ListFilter{}, err
+		}
+		filter.Cursor = &postDB.Cursor{CreatedAt: createdAt, ID: id}
+	}
+
+	return filter, nil
+}
+
+// respond отвечает постом вместе с его изображениями и реакциями
+func (h *Handler) respond(c *gin.Context, status int, post *postDB.Post) {
+	media, err := h.mediaRepo.ListPostMedia(c.Request.Context(), []int{post.ID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetPost, []int{post.ID})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(status, h.toResponse(post, media[post.ID], reactions[post.ID]))
+}
+
+// respondList отвечает страницей постов; изображения и реакции всех постов
+// страницы загружаются одним запросом каждые
+func (h *Handler) respondList(c *gin.Context, posts []*postDB.Post, limit int) {
+	ids := make([]int, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	media, err := h.mediaRepo.ListPostMedia(c.Request.Context(), ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	reactions, err := reactionHandler.Summaries(c, h.reactionRepo, reactionDB.TargetPost, ids)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toListResponse(posts, media, reactions, limit))
+}
+
+func (h *Handler) toListResponse(
+	posts []*postDB.Post,
+	media map[int][]*mediaDB.Media,
+	reactions map[int]*reactionDB.Summary,
+	limit int,
+) ListResponse {
+	resp := ListResponse{Items: make([]Response, 0, len(posts))}
+	for _, post := range posts {
+		resp.Items = append(resp.Items, h.toResponse(post, media[post.ID], reactions[post.ID]))
+	}
+
+	// Полная страница означает, что дальше могут быть еще посты
+	if len(posts) == limit {
+		last := posts[len(posts)-1]
+		resp.NextCursor = pagination.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return resp
+}
+
+func (h *Handler) toResponse(post *postDB.Post, media []*mediaDB.Media, reactions *reactionDB.Summary) Response {
+	resp := Response{
+		ID:           post.ID,
+		UserID:       post.UserID,
+		Body:         post.Body,
+		Media:        mediaHandler.ToResponses(h.media, media),
+		CommentCount: post.CommentCount,
+		Reactions:    reactionHandler.ToResponse(reactions),
+		CreatedAt:    post.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:    post.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if post.CarID != nil {
+		resp.Car = &CarResponse{
+			ID:    *post.CarID,
+			Make:  post.CarMake,
+			Model: post.CarModel,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,15 @@
+package reaction
+
+// SetRequest представляет структуру запроса на установку реакции
+type SetRequest struct {
+	Reaction string `json:"reaction" binding:"required,oneof=like love fire wow laugh sad" example:"fire"`
+}
+
+// Response представляет реакции на объект
+type Response struct {
+	// Counts - количество реакций каждого вида; виды без реакций не выводятся
+	Counts map[string]int `json:"counts"`
+	Total  int            `json:"total" example:"5"`
+	// Mine - реакция текущего пользователя
+	Mine string `json:"mine,omitempty" example:"fire"`
+}
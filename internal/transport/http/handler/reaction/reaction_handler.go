@@ -0,0 +1,157 @@
+package reaction
+
+import (
+	"net/http"
+	"strconv"
+
+	reactionDB "github.com/NikitaBelov-mobile/car-social/internal/database/reaction"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	reactionRepo reactionDB.ReactionRepository
+}
+
+func NewHandler(reactionRepo reactionDB.ReactionRepository) *Handler {
+	return &Handler{reactionRepo: reactionRepo}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	targets := []struct{ path, target string }{
+		{"/posts/:id/reactions", reactionDB.TargetPost},
+		{"/comments/:id/reactions", reactionDB.TargetComment},
+		{"/cars/:id/reactions", reactionDB.TargetCar},
+	}
+
+	for _, t := range targets {
+		router.PUT(t.path, h.set(t.target))       // Установка реакции
+		router.DELETE(t.path, h.delete(t.target)) // Снятие реакции
+	}
+}
+
+// Set godoc
+// @Summary Установка реакции
+// @Tags reactions
+// @Description Установка реакции текущего пользователя на пост, комментарий или машину. Пользователь может оставить одну реакцию на объект: новая заменяет прежнюю, повторная ничего не меняет.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID объекта"
+// @Param input body SetRequest true "Реакция"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "объект не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id}/reactions [put]
+// @Router /comments/{id}/reactions [put]
+// @Router /cars/{id}/reactions [put]
+func (h *Handler) set(target string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, targetID, ok := parseTarget(c)
+		if !ok {
+			return
+		}
+
+		var req SetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.InvalidRequest(err))
+			return
+		}
+
+		if err := h.reactionRepo.Set(c.Request.Context(), target, targetID, userID, req.Reaction); err != nil {
+			c.Error(err)
+			return
+		}
+
+		h.respond(c, target, targetID, userID)
+	}
+}
+
+// Delete godoc
+// @Summary Снятие реакции
+// @Tags reactions
+// @Description Снятие реакции текущего пользователя с поста, комментария или машины. Снятие отсутствующей реакции ошибкой не считается.
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID объекта"
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "объект не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /posts/{id}/reactions [delete]
+// @Router /comments/{id}/reactions [delete]
+// @Router /cars/{id}/reactions [delete]
+func (h *Handler) delete(target string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, targetID, ok := parseTarget(c)
+		if !ok {
+			return
+		}
+
+		if err := h.reactionRepo.Delete(c.Request.Context(), target, targetID, userID); err != nil {
+			c.Error(err)
+			return
+		}
+
+		h.respond(c, target, targetID, userID)
+	}
+}
+
+// parseTarget возвращает текущего пользователя и ID объекта из пути.
+// При ошибке ответ уже записан в контекст.
+func parseTarget(c *gin.Context) (int, int, bool) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return 0, 0, false
+	}
+
+	targetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(response.ErrInvalidID)
+		return 0, 0, false
+	}
+
+	return userID, targetID, true
+}
+
+// respond отвечает актуальной сводкой реакций на объект
+func (h *Handler) respond(c *gin.Context, target string, targetID, userID int) {
+	summaries, err := h.reactionRepo.Summaries(c.Request.Context(), target, []int{targetID}, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ToResponse(summaries[targetID]))
+}
+
+// Summaries загружает сводки реакций на объекты одним запросом для
+// текущего пользователя
+func Summaries(c *gin.Context, repo reactionDB.ReactionRepository, target string, targetIDs []int) (map[int]*reactionDB.Summary, error) {
+	// Без авторизации Mine остается пустым: пользователя с ID 0 нет
+	viewerID, _ := middleware.GetUserID(c)
+	return repo.Summaries(c.Request.Context(), target, targetIDs, viewerID)
+}
+
+func ToResponse(summary *reactionDB.Summary) Response {
+	resp := Response{Counts: map[string]int{}}
+	if summary == nil {
+		return resp
+	}
+
+	for reaction, count := range summary.Counts {
+		resp.Counts[reaction] = count
+		resp.Total += count
+	}
+	resp.Mine = summary.Mine
+
+	return resp
+}
@@ -0,0 +1,46 @@
+package search
+
+import "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+
+// UserResult представляет найденного пользователя
+type UserResult struct {
+	ID          int             `json:"id" example:"1"`
+	Nickname    *string         `json:"nickname" example:"supra_driver"`
+	DisplayName string          `json:"display_name" example:"Иван"`
+	AvatarURL   string          `json:"avatar_url" example:"https://example.com/avatar.jpg"`
+	Avatar      *media.Response `json:"avatar,omitempty"`
+}
+
+// CarResult представляет найденную машину
+type CarResult struct {
+	ID         int    `json:"id" example:"1"`
+	UserID     int    `json:"user_id" example:"1"`
+	Make       string `json:"make" example:"Toyota"`
+	Model      string `json:"model" example:"Supra"`
+	Generation string `json:"generation" example:"A80"`
+	Year       int    `json:"year" example:"1998"`
+}
+
+// PostResult представляет найденный пост
+type PostResult struct {
+	ID        int    `json:"id" example:"1"`
+	UserID    int    `json:"user_id" example:"1"`
+	Body      string `json:"body" example:"Поменял масло, едет как новая"`
+	CreatedAt string `json:"created_at" example:"2024-03-20 15:04:05"`
+}
+
+// Result представляет один результат поиска; заполнено поле,
+// соответствующее запрошенному type
+type Result struct {
+	User *UserResult `json:"user,omitempty"`
+	Car  *CarResult  `json:"car,omitempty"`
+	Post *PostResult `json:"post,omitempty"`
+	// Rank - релевантность результата; больше - выше в выдаче
+	Rank float64 `json:"rank" example:"0.0759"`
+}
+
+// ListResponse представляет страницу результатов поиска
+type ListResponse struct {
+	Items      []Result `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty" example:"MC4wNzU5fDE"`
+}
@@ -0,0 +1,282 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
+	searchDB "github.com/NikitaBelov-mobile/car-social/internal/database/search"
+	mediaService "github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	mediaHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+// Виды объектов поиска
+const (
+	typeUsers = "users"
+	typeCars  = "cars"
+	typePosts = "posts"
+)
+
+// maxQueryLength ограничивает длину текста запроса в символах
+const maxQueryLength = 200
+
+var (
+	errInvalidType    = apperror.InvalidInput("invalid_search_type", "type must be one of: users, cars, posts")
+	errQueryRequired  = apperror.InvalidInput("query_required", "search query is required")
+	errQueryTooLong   = apperror.InvalidInput("query_too_long", "search query is too long")
+	errInvalidYear    = apperror.InvalidInput("invalid_year", "invalid year filter")
+	errCarFilterUsage = apperror.InvalidInput("invalid_search_filter", "make, model and year filters apply only to cars")
+)
+
+type Handler struct {
+	searchRepo searchDB.SearchRepository
+	mediaRepo  mediaDB.MediaRepository
+	media      *mediaService.Service
+}
+
+func NewHandler(searchRepo searchDB.SearchRepository, mediaRepo mediaDB.MediaRepository, media *mediaService.Service) *Handler {
+	return &Handler{
+		searchRepo: searchRepo,
+		mediaRepo:  mediaRepo,
+		media:      media,
+	}
+}
+
+func (h *Handler) Register(router *gin.RouterGroup) {
+	router.GET("/search", h.search) // Поиск
+}
+
+// Search godoc
+// @Summary Поиск
+// @Tags search
+// @Description Полнотекстовый поиск пользователей, машин или постов на русском и английском с сортировкой по релевантности и cursor-пагинацией. Пользователи ищутся по имени, началу никнейма, а в открытых профилях - также по городу и описанию. Машины можно искать только по фильтрам, без текста запроса. Запрос поддерживает фразы в кавычках, OR и исключение слов через минус.
+// @Accept  json
+// @Produce  json
+// @Param q query string false "Текст запроса; обязателен для users и posts"
+// @Param type query string true "Что искать" Enums(users, cars, posts)
+// @Param make query string false "Марка машины (только для cars)"
+// @Param model query string false "Модель машины (только для cars)"
+// @Param year_from query int false "Год выпуска от (только для cars)"
+// @Param year_to query int false "Год выпуска до (только для cars)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Security BearerAuth
+// @Success 200 {object} ListResponse
+// @Failure 400 {object} response.ErrorResponse "неверные параметры запроса"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /search [get]
+func (h *Handler) search(c *gin.Context) {
+	searchType := c.Query("type")
+
+	query, err := parseQuery(c, searchType)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var items []Result
+	switch searchType {
+	case typeUsers:
+		items, err = h.searchUsers(c, query)
+	case typeCars:
+		items, err = h.searchCars(c, query)
+	case typePosts:
+		items, err = h.searchPosts(c, query)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toListResponse(items, query.Limit))
+}
+
+func (h *Handler) searchUsers(c *gin.Context, query searchDB.Query) ([]Result, error) {
+	users, err := h.searchRepo.Users(c.Request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Аватары всех пользователей страницы загружаются одним запросом
+	var mediaIDs []int
+	for _, user := range users {
+		if user.AvatarMediaID != nil {
+			mediaIDs = append(mediaIDs, *user.AvatarMediaID)
+		}
+	}
+
+	avatars := make(map[int]*mediaHandler.Response, len(mediaIDs))
+	if len(mediaIDs) > 0 {
+		media, err := h.mediaRepo.GetByIDs(c.Request.Context(), mediaIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range media {
+			resp := mediaHandler.ToResponse(h.media, m)
+			avatars[m.ID] = &resp
+		}
+	}
+
+	items := make([]Result, 0, len(users))
+	for _, user := range users {
+		result := &UserResult{
+			ID:          user.ID,
+			Nickname:    user.Nickname,
+			DisplayName: user.DisplayName,
+			AvatarURL:   user.AvatarURL,
+		}
+		if user.AvatarMediaID != nil {
+			if avatar := avatars[*user.AvatarMediaID]; avatar != nil {
+				result.Avatar = avatar
+				result.AvatarURL = avatar.URL
+			}
+		}
+
+		items = append(items, Result{User: result, Rank: user.Rank})
+	}
+
+	return items, nil
+}
+
+func (h *Handler) searchCars(c *gin.Context, query searchDB.Query) ([]Result, error) {
+	cars, err := h.searchRepo.Cars(c.Request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Result, 0, len(cars))
+	for _, car := range cars {
+		items = append(items, Result{
+			Car: &CarResult{
+				ID:         car.ID,
+				UserID:     car.UserID,
+				Make:       car.Make,
+				Model:      car.Model,
+				Generation: car.Generation,
+				Year:       car.Year,
+			},
+			Rank: car.Rank,
+		})
+	}
+
+	return items, nil
+}
+
+func (h *Handler) searchPosts(c *gin.Context, query searchDB.Query) ([]Result, error) {
+	posts, err := h.searchRepo.Posts(c.Request.Context(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Result, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, Result{
+			Post: &PostResult{
+				ID:        post.ID,
+				UserID:    post.UserID,
+				Body:      post.Body,
+				CreatedAt: post.CreatedAt.Format("2006-01-02 15:04:05"),
+			},
+			Rank: post.Rank,
+		})
+	}
+
+	return items, nil
+}
+
+func parseQuery(c *gin.Context, searchType string) (searchDB.Query, error) {
+	if searchType != typeUsers && searchType != typeCars && searchType != typePosts {
+		return searchDB.Query{}, errInvalidType
+	}
+
+	limit, err := pagination.ParseLimit(c.Query("limit"))
+	if err != nil {
+		return searchDB.Query{}, err
+	}
+
+	query := searchDB.Query{
+		Text:  strings.TrimSpace(c.Query("q")),
+		Make:  strings.TrimSpace(c.Query("make")),
+		Model: strings.TrimSpace(c.Query("model")),
+		Limit: limit,
+	}
+
+	if utf8.RuneCountInString(query.Text) > maxQueryLength {
+		return searchDB.Query{}, errQueryTooLong
+	}
+
+	if query.YearFrom, err = parseYear(c.Query("year_from")); err != nil {
+		return searchDB.Query{}, err
+	}
+	if query.YearTo, err = parseYear(c.Query("year_to")); err != nil {
+		return searchDB.Query{}, err
+	}
+
+	hasCarFilter := query.Make != "" || query.Model != "" || query.YearFrom > 0 || query.YearTo > 0
+	if searchType == typeCars {
+		if query.Text == "" && !hasCarFilter {
+			return searchDB.Query{}, errQueryRequired
+		}
+	} else {
+		if hasCarFilter {
+			return searchDB.Query{}, errCarFilterUsage
+		}
+		if query.Text == "" {
+			return searchDB.Query{}, errQueryRequired
+		}
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		rank, id, err := pagination.DecodeRankCursor(cursor)
+		if err != nil {
+			return searchDB.Query{}, err
+		}
+		query.Cursor = &searchDB.Cursor{Rank: rank, ID: id}
+	}
+
+	return query, nil
+}
+
+// parseYear разбирает необязательный фильтр по году; 0 означает его отсутствие
+func parseYear(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	year, err := strconv.Atoi(value)
+	if err != nil || year <= 0 {
+		return 0, errInvalidYear
+	}
+
+	return year, nil
+}
+
+func toListResponse(items []Result, limit int) ListResponse {
+	resp := ListResponse{Items: items}
+
+	// Полная страница означает, что дальше могут быть еще результаты
+	if len(items) == limit {
+		last := items[len(items)-1]
+		resp.NextCursor = pagination.EncodeRankCursor(last.Rank, last.id())
+	}
+
+	return resp
+}
+
+func (r Result) id() int {
+	switch {
+	case r.User != nil:
+		return r.User.ID
+	case r.Car != nil:
+		return r.Car.ID
+	default:
+		return r.Post.ID
+	}
+}
@@ -1,24 +1,71 @@
 package user
 
+import "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+
 type CreateRequest struct {
 	Phone    string `json:"phone" binding:"required"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
-// UpdateRequest представляет структуру запроса на обновление пользователя
+// UpdateRequest представляет структуру запроса на обновление пользователя;
+// для смены пароля обязателен текущий пароль
 type UpdateRequest struct {
-	Phone    string `json:"phone,omitempty" example:"79991234567"`
-	Password string `json:"password,omitempty" example:"newpassword123" binding:"omitempty,min=6"`
+	Phone           string `json:"phone,omitempty" example:"+79991234567"`
+	Password        string `json:"password,omitempty" example:"newpassword123" binding:"omitempty,min=6"`
+	CurrentPassword string `json:"current_password,omitempty" example:"oldpassword123" binding:"required_with=Password"`
 }
 
 // Response представляет структуру ответа с данными пользователя
 type Response struct {
-	ID        int    `json:"id" example:"1"`
-	Phone     string `json:"phone" example:"79991234567"`
-	CreatedAt string `json:"created_at" example:"2024-03-20 15:04:05"`
+	ID            int    `json:"id" example:"1"`
+	Phone         string `json:"phone" example:"+79991234567"`
+	PhoneVerified bool   `json:"phone_verified" example:"true"`
+	CreatedAt     string `json:"created_at" example:"2024-03-20 15:04:05"`
+}
+
+// UpdateProfileRequest представляет структуру запроса на изменение профиля;
+// переданные поля заменяются, пустая строка очищает поле
+type UpdateProfileRequest struct {
+	Nickname    *string `json:"nickname,omitempty" example:"supra_driver"`
+	DisplayName *string `json:"display_name,omitempty" binding:"omitempty,max=100" example:"Иван"`
+	Bio         *string `json:"bio,omitempty" binding:"omitempty,max=500" example:"Люблю японские машины 90-х"`
+	City        *string `json:"city,omitempty" binding:"omitempty,max=100" example:"Москва"`
+	AvatarURL   *string `json:"avatar_url,omitempty" binding:"omitempty,max=500,len=0|url" example:"https://cdn.example.com/avatars/1.jpg"`
+	// AvatarMediaID - изображение, загруженное через /media; 0 убирает аватар
+	AvatarMediaID *int    `json:"avatar_media_id,omitempty" binding:"omitempty,min=0" example:"3"`
+	Visibility    *string `json:"visibility,omitempty" binding:"omitempty,oneof=public private" example:"private"`
+}
+
+// ProfileResponse представляет профиль текущего пользователя вместе
+// с приватными данными
+type ProfileResponse struct {
+	ID            int    `json:"id" example:"1"`
+	Phone         string `json:"phone" example:"+79991234567"`
+	PhoneVerified bool   `json:"phone_verified" example:"true"`
+	Nickname      string `json:"nickname,omitempty" example:"supra_driver"`
+	DisplayName   string `json:"display_name" example:"Иван"`
+	Bio           string `json:"bio" example:"Люблю японские машины 90-х"`
+	City          string `json:"city" example:"Москва"`
+	AvatarURL     string `json:"avatar_url" example:"https://cdn.example.com/avatars/1.jpg"`
+	// Avatar - загруженный аватар с миниатюрами
+	Avatar     *media.Response `json:"avatar,omitempty"`
+	Visibility string          `json:"visibility" example:"public"`
+	CreatedAt  string          `json:"created_at" example:"2024-03-20 15:04:05"`
 }
 
-// ErrorResponse представляет структуру ответа с ошибкой
-type ErrorResponse struct {
-	Error string `json:"error" example:"описание ошибки"`
+// PublicProfileResponse представляет профиль пользователя, видимый другим
+// пользователям. Номер телефона не раскрывается никогда, а bio и city
+// скрываются, если профиль закрыт настройками приватности.
+type PublicProfileResponse struct {
+	ID          int    `json:"id" example:"1"`
+	Nickname    string `json:"nickname,omitempty" example:"supra_driver"`
+	DisplayName string `json:"display_name" example:"Иван"`
+	AvatarURL   string `json:"avatar_url" example:"https://cdn.example.com/avatars/1.jpg"`
+	// Avatar - загруженный аватар с миниатюрами
+	Avatar *media.Response `json:"avatar,omitempty"`
+	Bio    string          `json:"bio,omitempty" example:"Люблю японские машины 90-х"`
+	City   string          `json:"city,omitempty" example:"Москва"`
+	// Restricted - часть профиля скрыта настройками приватности
+	Restricted bool   `json:"restricted" example:"false"`
+	CreatedAt  string `json:"created_at" example:"2024-03-20 15:04:05"`
 }
@@ -1,105 +1,346 @@
 package user
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"regexp"
 	"strconv"
 
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	mediaDB "github.com/NikitaBelov-mobile/car-social/internal/database/media"
 	userDB "github.com/NikitaBelov-mobile/car-social/internal/database/user"
-	"golang.org/x/crypto/bcrypt"
-
+	mediaService "github.com/NikitaBelov-mobile/car-social/internal/service/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/password"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/phone"
+	mediaHandler "github.com/NikitaBelov-mobile/car-social/internal/transport/http/handler/media"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/middleware"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
 	"github.com/gin-gonic/gin"
 )
 
+// nicknamePattern - допустимые никнеймы: латиница, цифры, "_" и "."
+var nicknamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.]{3,30}$`)
+
+var errInvalidNickname = apperror.InvalidInput("invalid_nickname", "nickname must be 3-30 characters: letters, digits, '_' or '.'")
+
 type Handler struct {
-	userRepo userDB.UserRepository
+	userRepo    userDB.UserRepository
+	profileRepo userDB.ProfileRepository
+	mediaRepo   mediaDB.MediaRepository
+	media       *mediaService.Service
+	phones      *phone.Normalizer
+	passwords   password.PasswordHasher
+	logger      *slog.Logger
 }
 
-func NewHandler(userRepo userDB.UserRepository) *Handler {
+func NewHandler(
+	userRepo userDB.UserRepository,
+	profileRepo userDB.ProfileRepository,
+	mediaRepo mediaDB.MediaRepository,
+	media *mediaService.Service,
+	phones *phone.Normalizer,
+	passwords password.PasswordHasher,
+	logger *slog.Logger,
+) *Handler {
 	return &Handler{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		mediaRepo:   mediaRepo,
+		media:       media,
+		phones:      phones,
+		passwords:   passwords,
+		logger:      logger,
 	}
 }
 
 func (h *Handler) Register(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
-		users.GET("/:id", h.getByID) // Получение пользователя по ID
-		users.PUT("/:id", h.update)  // Обновление данных пользователя
+		users.GET("/:id", h.getByID)                            // Публичный профиль пользователя
+		users.PUT("/:id", middleware.OwnerOnly("id"), h.update) // Обновление данных пользователя
 	}
+
+	router.GET("/me", h.getMe)      // Профиль текущего пользователя
+	router.PATCH("/me", h.updateMe) // Изменение профиля текущего пользователя
 }
 
 func (h *Handler) create(c *gin.Context) {
 	var req CreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(response.InvalidRequest(err))
 		return
 	}
 
-	// Проверяем, существует ли пользователь
-	existingUser, err := h.userRepo.GetByPhone(req.Phone)
-	if err == nil && existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "user already exists"})
+	phoneNumber, err := h.phones.Normalize(req.Phone)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	// Хешируем пароль
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := h.passwords.Hash(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+		c.Error(err)
 		return
 	}
 
 	// Создаем нового пользователя
 	user := &userDB.User{
-		Phone:        req.Phone,
-		PasswordHash: string(hashedPassword),
+		Phone:        phoneNumber,
+		PasswordHash: passwordHash,
 	}
 
-	if err := h.userRepo.Create(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
+	if err := h.userRepo.Create(c.Request.Context(), user); err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, Response{
-		ID:        user.ID,
-		Phone:     user.Phone,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:            user.ID,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerifiedAt != nil,
+		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
 	})
 }
 
 // GetByID godoc
-// @Summary Получение пользователя
+// @Summary Публичный профиль пользователя
 // @Tags users
-// @Description Получение информации о пользователе по ID
+// @Description Профиль пользователя по ID без номера телефона. Bio и город скрываются, если владелец ограничил видимость профиля.
 // @Accept  json
 // @Produce  json
 // @Param id path int true "ID пользователя"
 // @Security BearerAuth
-// @Success 200 {object} Response
-// @Failure 400 {object} ErrorResponse "неверный формат ID"
-// @Failure 404 {object} ErrorResponse "пользователь не найден"
-// @Failure 500 {object} ErrorResponse "внутренняя ошибка сервера"
+// @Success 200 {object} PublicProfileResponse
+// @Failure 400 {object} response.ErrorResponse "неверный формат ID"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /users/{id} [get]
 func (h *Handler) getByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		c.Error(response.ErrInvalidID)
 		return
 	}
 
-	user, err := h.userRepo.GetByID(id)
+	viewerID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	profile, err := h.profileRepo.GetProfile(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, Response{
-		ID:        user.ID,
-		Phone:     user.Phone,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+	// Закрытую часть профиля видит только владелец
+	visible := profile.UserID == viewerID || profile.Visibility == userDB.VisibilityPublic
+
+	avatar, err := h.avatar(c, profile)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := PublicProfileResponse{
+		ID:          profile.UserID,
+		Nickname:    nickname(profile),
+		DisplayName: profile.DisplayName,
+		AvatarURL:   avatarURL(profile, avatar),
+		Avatar:      avatar,
+		Restricted:  !visible,
+		CreatedAt:   profile.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if visible {
+		resp.Bio = profile.Bio
+		resp.City = profile.City
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetMe godoc
+// @Summary Профиль текущего пользователя
+// @Tags users
+// @Description Профиль текущего пользователя вместе с номером телефона и настройками приватности
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Success 200 {object} ProfileResponse
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /me [get]
+func (h *Handler) getMe(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	profile, err := h.profileRepo.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondProfile(c, user, profile)
+}
+
+// UpdateMe godoc
+// @Summary Изменение профиля
+// @Tags users
+// @Description Частичное изменение профиля текущего пользователя: переданные поля заменяются, пустая строка очищает поле. Никнейм уникален без учета регистра. Аватар задается через avatar_media_id изображения, загруженного через /media, и имеет приоритет над avatar_url.
+// @Accept  json
+// @Produce  json
+// @Param input body UpdateProfileRequest true "Изменяемые поля профиля"
+// @Security BearerAuth
+// @Success 200 {object} ProfileResponse
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 409 {object} response.ErrorResponse "никнейм уже занят"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
+// @Router /me [patch]
+func (h *Handler) updateMe(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.Error(response.ErrUnauthorized)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidRequest(err))
+		return
+	}
+
+	if req.Nickname != nil && *req.Nickname != "" && !nicknamePattern.MatchString(*req.Nickname) {
+		c.Error(errInvalidNickname)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	profile, err := h.profileRepo.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.Nickname != nil {
+		profile.Nickname = nil
+		if *req.Nickname != "" {
+			profile.Nickname = req.Nickname
+		}
+	}
+	if req.DisplayName != nil {
+		profile.DisplayName = *req.DisplayName
+	}
+	if req.Bio != nil {
+		profile.Bio = *req.Bio
+	}
+	if req.City != nil {
+		profile.City = *req.City
+	}
+	if req.AvatarURL != nil {
+		profile.AvatarURL = *req.AvatarURL
+	}
+	if req.Visibility != nil {
+		profile.Visibility = *req.Visibility
+	}
+	if req.AvatarMediaID != nil {
+		profile.AvatarMediaID = nil
+		if *req.AvatarMediaID != 0 {
+			if err := h.media.CheckOwned(c.Request.Context(), userID, []int{*req.AvatarMediaID}); err != nil {
+				c.Error(err)
+				return
+			}
+			profile.AvatarMediaID = req.AvatarMediaID
+		}
+	}
+
+	if err := h.profileRepo.UpdateProfile(c.Request.Context(), profile); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respondProfile(c, user, profile)
+}
+
+// respondProfile отвечает профилем текущего пользователя
+func (h *Handler) respondProfile(c *gin.Context, user *userDB.User, profile *userDB.Profile) {
+	avatar, err := h.avatar(c, profile)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ProfileResponse{
+		ID:            user.ID,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerifiedAt != nil,
+		Nickname:      nickname(profile),
+		DisplayName:   profile.DisplayName,
+		Bio:           profile.Bio,
+		City:          profile.City,
+		AvatarURL:     avatarURL(profile, avatar),
+		Avatar:        avatar,
+		Visibility:    profile.Visibility,
+		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
 	})
 }
 
+// avatar загружает аватар профиля, если он задан через media. Аватар,
+// удаленный между чтением профиля и этим запросом, считается отсутствующим.
+func (h *Handler) avatar(c *gin.Context, profile *userDB.Profile) (*mediaHandler.Response, error) {
+	if profile.AvatarMediaID == nil {
+		return nil, nil
+	}
+
+	m, err := h.mediaRepo.GetByID(c.Request.Context(), *profile.AvatarMediaID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	resp := mediaHandler.ToResponse(h.media, m)
+	return &resp, nil
+}
+
+func avatarURL(profile *userDB.Profile, avatar *mediaHandler.Response) string {
+	if avatar != nil {
+		return avatar.URL
+	}
+	return profile.AvatarURL
+}
+
+func nickname(profile *userDB.Profile) string {
+	if profile.Nickname == nil {
+		return ""
+	}
+	return *profile.Nickname
+}
+
 // Update godoc
 // @Summary Обновление пользователя
 // @Tags users
@@ -110,59 +351,78 @@ func (h *Handler) getByID(c *gin.Context) {
 // @Param input body UpdateRequest true "Данные для обновления"
 // @Security BearerAuth
 // @Success 200 {object} Response
-// @Failure 400 {object} ErrorResponse "неверный формат данных"
-// @Failure 404 {object} ErrorResponse "пользователь не найден"
-// @Failure 409 {object} ErrorResponse "телефон уже занят"
-// @Failure 500 {object} ErrorResponse "внутренняя ошибка сервера"
+// @Failure 400 {object} response.ErrorResponse "неверный формат данных"
+// @Failure 401 {object} response.ErrorResponse "требуется авторизация"
+// @Failure 403 {object} response.ErrorResponse "нет доступа к пользователю или неверный текущий пароль"
+// @Failure 404 {object} response.ErrorResponse "пользователь не найден"
+// @Failure 409 {object} response.ErrorResponse "телефон уже занят"
+// @Failure 500 {object} response.ErrorResponse "внутренняя ошибка сервера"
+// @Failure 503 {object} response.ErrorResponse "база данных недоступна"
 // @Router /users/{id} [put]
 func (h *Handler) update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format"})
+		c.Error(response.ErrInvalidID)
 		return
 	}
 
 	var req UpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(response.InvalidRequest(err))
 		return
 	}
 
 	// Получаем существующего пользователя
-	user, err := h.userRepo.GetByID(id)
+	user, err := h.userRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		c.Error(err)
 		return
 	}
 
 	// Обновляем только переданные поля
 	if req.Phone != "" {
-		// Проверяем, не занят ли телефон другим пользователем
-		existingUser, err := h.userRepo.GetByPhone(req.Phone)
-		if err == nil && existingUser != nil && existingUser.ID != id {
-			c.JSON(http.StatusConflict, gin.H{"error": "phone number already taken"})
+		phoneNumber, err := h.phones.Normalize(req.Phone)
+		if err != nil {
+			c.Error(err)
 			return
 		}
-		user.Phone = req.Phone
+
+		user.Phone = phoneNumber
 	}
 
 	if req.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		ok, err := h.passwords.Verify(user.PasswordHash, req.CurrentPassword)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if !ok {
+			h.logger.WarnContext(c.Request.Context(), "password change rejected: invalid current password")
+			c.Error(apperror.Forbidden("invalid_current_password", "invalid current password"))
+			return
+		}
+
+		passwordHash, err := h.passwords.Hash(req.Password)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+			c.Error(err)
 			return
 		}
-		user.PasswordHash = string(hashedPassword)
+		user.PasswordHash = passwordHash
 	}
 
-	if err := h.userRepo.Update(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
+	if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
+		c.Error(err)
 		return
 	}
 
+	if req.Password != "" {
+		h.logger.InfoContext(c.Request.Context(), "password changed")
+	}
+
 	c.JSON(http.StatusOK, Response{
-		ID:        user.ID,
-		Phone:     user.Phone,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:            user.ID,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerifiedAt != nil,
+		CreatedAt:     user.CreatedAt.Format("2006-01-02 15:04:05"),
 	})
 }
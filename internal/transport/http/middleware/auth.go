@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/logger"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/token"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer"
+
+	userIDKey = "userID"
+)
+
+// Auth проверяет заголовок "Authorization: Bearer <token>" и сохраняет
+// ID пользователя из access token в контексте запроса
+func Auth(tokenManager *token.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(authorizationHeader)
+		if header == "" {
+			c.Error(apperror.Unauthorized("empty_authorization_header", "empty authorization header"))
+			c.Abort()
+			return
+		}
+
+		parts := strings.Fields(header)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], bearerPrefix) {
+			c.Error(apperror.Unauthorized("invalid_authorization_header", "invalid authorization header"))
+			c.Abort()
+			return
+		}
+
+		userID, err := tokenManager.ParseToken(parts[1])
+		if err != nil {
+			c.Error(apperror.Unauthorized("invalid_access_token", "invalid access token"))
+			c.Abort()
+			return
+		}
+
+		c.Set(userIDKey, userID)
+		c.Request = c.Request.WithContext(logger.WithAttrs(c.Request.Context(), slog.Int("user_id", userID)))
+		c.Next()
+	}
+}
+
+// OwnerOnly пропускает запрос только если ID из пути совпадает с ID
+// пользователя из токена. Должен подключаться после Auth.
+func OwnerOnly(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param(param))
+		if err != nil {
+			c.Error(response.ErrInvalidID)
+			c.Abort()
+			return
+		}
+
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.Error(response.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		if userID != id {
+			c.Error(response.ErrAccessDenied)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetUserID возвращает ID пользователя, сохраненный middleware Auth
+func GetUserID(c *gin.Context) (int, bool) {
+	value, ok := c.Get(userIDKey)
+	if !ok {
+		return 0, false
+	}
+
+	userID, ok := value.(int)
+	return userID, ok
+}
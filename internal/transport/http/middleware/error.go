@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/database"
+	"github.com/NikitaBelov-mobile/car-social/internal/transport/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Errors превращает ошибку, добавленную обработчиком через c.Error, в ответ
+// формата response.ErrorResponse. Должен подключаться после RequestID и до
+// остальных middleware.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+
+		var retry retryAfter
+		if errors.As(err, &retry) {
+			c.Header("Retry-After", retryAfterSeconds(retry.RetryAfter()))
+		}
+
+		status, resp := errorResponse(err)
+		resp.RequestID = GetRequestID(c)
+		c.JSON(status, resp)
+	}
+}
+
+// retryAfter реализуют ошибки, созданные apperror.WithRetryAfter
+type retryAfter interface {
+	RetryAfter() time.Duration
+}
+
+// retryAfterSeconds округляет время вверх до целых секунд, как требует
+// заголовок Retry-After
+func retryAfterSeconds(d time.Duration) string {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
+func errorResponse(err error) (int, response.ErrorResponse) {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return errorStatus(appErr.Kind), response.ErrorResponse{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		}
+	}
+
+	// Подробности внутренних ошибок клиенту не отдаем, они попадают в лог
+	// запроса через c.Errors
+	if database.IsUnavailable(err) {
+		return http.StatusServiceUnavailable, response.ErrorResponse{
+			Code:    response.CodeServiceUnavailable,
+			Message: "service temporarily unavailable",
+		}
+	}
+
+	return http.StatusInternalServerError, response.ErrorResponse{
+		Code:    response.CodeInternal,
+		Message: "internal server error",
+	}
+}
+
+func errorStatus(kind error) int {
+	switch kind {
+	case apperror.ErrNotFound:
+		return http.StatusNotFound
+	case apperror.ErrConflict:
+		return http.StatusConflict
+	case apperror.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case apperror.ErrForbidden:
+		return http.StatusForbidden
+	case apperror.ErrInvalidInput:
+		return http.StatusBadRequest
+	case apperror.ErrTooManyRequests:
+		return http.StatusTooManyRequests
+	case apperror.ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case apperror.ErrUnsupportedType:
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog пишет по одной записи на каждый запрос: метод, шаблон маршрута,
+// статус, время обработки и ID пользователя. Для ответов 5xx в запись
+// добавляется внутренняя ошибка, которая не отдается клиенту. Должен
+// подключаться после RequestID и до Errors.
+func AccessLog(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+			if len(c.Errors) > 0 {
+				attrs = append(attrs, slog.String("error", c.Errors.Last().Error()))
+			}
+		}
+
+		log.LogAttrs(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+// Recovery перехватывает панику в обработчике, пишет ее в лог со стеком и
+// передает в Errors как внутреннюю ошибку. Должен подключаться после Errors.
+func Recovery(log *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		log.ErrorContext(c.Request.Context(), "panic recovered",
+			"panic", recovered,
+			"stack", string(debug.Stack()),
+		)
+		c.Error(fmt.Errorf("panic: %v", recovered))
+		c.Abort()
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute - значение метки route для запросов, не попавших ни в один
+// маршрут
+const unmatchedRoute = "unmatched"
+
+// Metrics учитывает запросы в метриках HTTP по шаблону маршрута. Должен
+// подключаться до Errors, чтобы видеть итоговый статус ответа.
+func Metrics(m *metrics.HTTP) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.Started()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		m.Finished(route, c.Request.Method, c.Writer.Status(), time.Since(start))
+	}
+}
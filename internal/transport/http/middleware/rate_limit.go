@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+	"github.com/NikitaBelov-mobile/car-social/internal/service/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+var errRateLimited = apperror.TooManyRequests("rate_limited", "too many requests")
+
+// RateKey выбирает, чьи запросы учитываются в одной корзине
+type RateKey func(c *gin.Context) string
+
+// ByIP считает запросы с одного IP вместе
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser считает запросы одного пользователя вместе; для запросов без
+// access token используется IP. Должен подключаться после Auth.
+func ByUser(c *gin.Context) string {
+	if userID, ok := GetUserID(c); ok {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return ByIP(c)
+}
+
+// RateLimit ограничивает частоту запросов к группе маршрутов. scope
+// разделяет корзины разных политик, поэтому одна и та же политика может
+// подключаться к нескольким группам с общим лимитом. При превышении лимита
+// отвечает 429 с заголовком Retry-After.
+func RateLimit(limiter *ratelimit.Limiter, scope string, limit ratelimit.Limit, key RateKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), scope+":"+key(c), limit)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if !result.Allowed {
+			c.Error(apperror.WithRetryAfter(errRateLimited, result.RetryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
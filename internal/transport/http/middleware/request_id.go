@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+
+	requestIDKey = "requestID"
+)
+
+// RequestID берет ID запроса из заголовка X-Request-ID или генерирует новый,
+// сохраняет его в контексте и возвращает в одноименном заголовке ответа.
+// ID запроса также добавляется ко всем записям лога, сделанным с
+// контекстом запроса.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Request = c.Request.WithContext(logger.WithAttrs(c.Request.Context(), slog.String("request_id", requestID)))
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// GetRequestID возвращает ID запроса, сохраненный middleware RequestID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,104 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikitaBelov-mobile/car-social/internal/apperror"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = apperror.InvalidInput("invalid_cursor", "invalid cursor")
+	ErrInvalidLimit  = apperror.InvalidInput("invalid_limit", "invalid limit")
+)
+
+// EncodeCursor упаковывает позицию (created_at, id) последнего элемента
+// страницы в непрозрачную для клиента строку
+func EncodeCursor(createdAt time.Time, id int) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor разбирает строку, полученную из EncodeCursor
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	createdAtPart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtPart)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idPart)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	return createdAt, id, nil
+}
+
+// ParseLimit разбирает размер страницы из query-параметра,
+// подставляя значение по умолчанию и ограничивая максимум
+func ParseLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, ErrInvalidLimit
+	}
+
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	return limit, nil
+}
+
+// EncodeRankCursor упаковывает позицию (rank, id) последнего элемента
+// страницы результатов, упорядоченных по релевантности
+func EncodeRankCursor(rank float64, id int) string {
+	raw := strconv.FormatFloat(rank, 'g', -1, 64) + "|" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeRankCursor разбирает строку, полученную из EncodeRankCursor
+func DecodeRankCursor(cursor string) (float64, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	rankPart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	rank, err := strconv.ParseFloat(rankPart, 64)
+	if err != nil || math.IsNaN(rank) || math.IsInf(rank, 0) {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idPart)
+	if err != nil {
+		return 0, 0, ErrInvalidCursor
+	}
+
+	return rank, id, nil
+}
@@ -0,0 +1,30 @@
+package response
+
+import "github.com/NikitaBelov-mobile/car-social/internal/apperror"
+
+// ErrorResponse - единый формат ответа с ошибкой
+type ErrorResponse struct {
+	// Code - машиночитаемый код ошибки
+	Code      string `json:"code" example:"user_not_found"`
+	Message   string `json:"message" example:"user not found"`
+	RequestID string `json:"request_id" example:"0b6f1c9e3a2d4e5f8a7b6c5d4e3f2a1b"`
+}
+
+// Коды ошибок, не привязанные к предметной области
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeInternal           = "internal_error"
+	CodeServiceUnavailable = "service_unavailable"
+)
+
+// Ошибки, общие для всех обработчиков
+var (
+	ErrInvalidID    = apperror.InvalidInput("invalid_id", "invalid id format")
+	ErrUnauthorized = apperror.Unauthorized("unauthorized", "unauthorized")
+	ErrAccessDenied = apperror.Forbidden("access_denied", "access denied")
+)
+
+// InvalidRequest оборачивает ошибку разбора или валидации тела запроса
+func InvalidRequest(err error) error {
+	return apperror.InvalidInput(CodeInvalidRequest, err.Error())
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cars;
\ No newline at end of file
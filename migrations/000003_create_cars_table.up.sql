@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS cars (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    make VARCHAR(100) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    generation VARCHAR(100) NOT NULL DEFAULT '',
+    year INTEGER NOT NULL,
+    vin VARCHAR(17) NOT NULL DEFAULT '',
+    color VARCHAR(50) NOT NULL DEFAULT '',
+    engine VARCHAR(100) NOT NULL DEFAULT '',
+    mileage INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_cars_user_id ON cars(user_id);
+CREATE INDEX idx_cars_make_model ON cars(make, model);
+-- У пользователя может быть только одна основная машина
+CREATE UNIQUE INDEX idx_cars_user_primary ON cars(user_id) WHERE is_primary;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS posts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    car_id INTEGER REFERENCES cars(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Keyset-пагинация по (created_at, id)
+CREATE INDEX idx_posts_user_created ON posts(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_posts_created ON posts(created_at DESC, id DESC);
+CREATE INDEX idx_posts_car_id ON posts(car_id);
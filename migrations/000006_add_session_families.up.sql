@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    ADD COLUMN family_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN consumed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
@@ -0,0 +1,12 @@
+DELETE FROM sessions;
+
+DROP INDEX IF EXISTS idx_sessions_expires_at;
+
+ALTER TABLE sessions
+    ALTER COLUMN expires_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP,
+    ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE sessions RENAME CONSTRAINT sessions_refresh_token_hash_key TO sessions_refresh_token_key;
+ALTER TABLE sessions RENAME COLUMN refresh_token_hash TO refresh_token;
+CREATE INDEX idx_sessions_refresh_token ON sessions(refresh_token);
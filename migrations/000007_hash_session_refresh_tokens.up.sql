@@ -0,0 +1,13 @@
+-- Токены, сохраненные в открытом виде, становятся недействительными
+DELETE FROM sessions;
+
+DROP INDEX IF EXISTS idx_sessions_refresh_token;
+ALTER TABLE sessions RENAME COLUMN refresh_token TO refresh_token_hash;
+ALTER TABLE sessions RENAME CONSTRAINT sessions_refresh_token_key TO sessions_refresh_token_hash_key;
+
+ALTER TABLE sessions
+    ALTER COLUMN expires_at TYPE TIMESTAMP WITH TIME ZONE,
+    ALTER COLUMN created_at TYPE TIMESTAMP WITH TIME ZONE,
+    ALTER COLUMN updated_at TYPE TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
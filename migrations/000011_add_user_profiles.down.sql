@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_nickname_lower;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS nickname,
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS city,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS profile_visibility;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN nickname VARCHAR(30),
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN city VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN profile_visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+        CHECK (profile_visibility IN ('public', 'followers', 'private'));
+
+-- Никнеймы уникальны без учета регистра
+CREATE UNIQUE INDEX idx_users_nickname_lower ON users(LOWER(nickname));
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS car_photos;
+DROP TABLE IF EXISTS post_media;
+
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_media_id;
+
+DROP TABLE IF EXISTS media;
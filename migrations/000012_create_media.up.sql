@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS media (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    -- SHA-256 сохраненного файла в hex
+    checksum CHAR(64) NOT NULL,
+    thumbnails JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_media_owner ON media(owner_id, created_at DESC);
+CREATE INDEX idx_media_checksum ON media(checksum);
+
+ALTER TABLE users ADD COLUMN avatar_media_id INTEGER REFERENCES media(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS post_media (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    media_id INTEGER NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    PRIMARY KEY (post_id, media_id)
+);
+
+CREATE INDEX idx_post_media_media_id ON post_media(media_id);
+
+CREATE TABLE IF NOT EXISTS car_photos (
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    media_id INTEGER NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    PRIMARY KEY (car_id, media_id)
+);
+
+CREATE INDEX idx_car_photos_media_id ON car_photos(media_id);
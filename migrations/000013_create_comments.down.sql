@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS comment_count;
+
+DROP TABLE IF EXISTS comments;
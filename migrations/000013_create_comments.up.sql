@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Ответы допускаются только на комментарии верхнего уровня
+    parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    reply_count INTEGER NOT NULL DEFAULT 0 CHECK (reply_count >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Keyset-пагинация по (created_at, id) отдельно для верхнего уровня и ответов
+CREATE INDEX idx_comments_post_created ON comments(post_id, created_at, id) WHERE parent_id IS NULL;
+CREATE INDEX idx_comments_parent_created ON comments(parent_id, created_at, id);
+CREATE INDEX idx_comments_user_id ON comments(user_id);
+
+-- Счетчик комментариев поста вместе с ответами; поддерживается в одной
+-- транзакции с созданием и удалением комментариев
+ALTER TABLE posts ADD COLUMN comment_count INTEGER NOT NULL DEFAULT 0 CHECK (comment_count >= 0);
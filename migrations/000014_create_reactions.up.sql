@@ -0,0 +1,30 @@
+-- Реакции хранятся в отдельной таблице для каждого вида объектов, чтобы
+-- внешние ключи удаляли их вместе с объектом. Составной первичный ключ
+-- допускает одну реакцию пользователя на объект.
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL CHECK (reaction IN ('like', 'love', 'fire', 'wow', 'laugh', 'sad')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL CHECK (reaction IN ('like', 'love', 'fire', 'wow', 'laugh', 'sad')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS car_reactions (
+    car_id INTEGER NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(20) NOT NULL CHECK (reaction IN ('like', 'love', 'fire', 'wow', 'laugh', 'sad')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (car_id, user_id)
+);
+
+CREATE INDEX idx_post_reactions_user_id ON post_reactions(user_id);
+CREATE INDEX idx_comment_reactions_user_id ON comment_reactions(user_id);
+CREATE INDEX idx_car_reactions_user_id ON car_reactions(user_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_posts_search;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_cars_make_model_year_lower;
+DROP INDEX IF EXISTS idx_cars_search;
+ALTER TABLE cars DROP COLUMN IF EXISTS search_vector;
+
+DROP INDEX IF EXISTS idx_users_nickname_prefix;
+DROP INDEX IF EXISTS idx_users_search;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
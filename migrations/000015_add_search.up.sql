@@ -0,0 +1,38 @@
+-- Поисковые векторы строятся по русской и английской конфигурациям: тексты
+-- пользователи пишут на обоих языках. Запрос разбирается теми же
+-- конфигурациями, поэтому словоформы совпадают в любом из языков.
+
+-- Город и описание участвуют в поиске только у открытых профилей, чтобы
+-- поиск не раскрывал данные закрытых
+ALTER TABLE users ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian'::regconfig, display_name), 'A') ||
+    setweight(to_tsvector('english'::regconfig, display_name), 'A') ||
+    CASE WHEN profile_visibility = 'public' THEN
+        setweight(to_tsvector('russian'::regconfig, city), 'B') ||
+        setweight(to_tsvector('english'::regconfig, city), 'B') ||
+        setweight(to_tsvector('russian'::regconfig, bio), 'C') ||
+        setweight(to_tsvector('english'::regconfig, bio), 'C')
+    ELSE ''::tsvector END
+) STORED;
+
+CREATE INDEX idx_users_search ON users USING GIN (search_vector);
+-- Поиск по началу никнейма: LIKE 'prefix%' использует индекс только
+-- с text_pattern_ops
+CREATE INDEX idx_users_nickname_prefix ON users(LOWER(nickname) text_pattern_ops);
+
+ALTER TABLE cars ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple'::regconfig, make || ' ' || model), 'A') ||
+    setweight(to_tsvector('simple'::regconfig, generation || ' ' || engine), 'B') ||
+    setweight(to_tsvector('russian'::regconfig, color), 'C') ||
+    setweight(to_tsvector('english'::regconfig, color), 'C')
+) STORED;
+
+CREATE INDEX idx_cars_search ON cars USING GIN (search_vector);
+CREATE INDEX idx_cars_make_model_year_lower ON cars(LOWER(make), LOWER(model), year);
+
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('russian'::regconfig, body) ||
+    to_tsvector('english'::regconfig, body)
+) STORED;
+
+CREATE INDEX idx_posts_search ON posts USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_profile_visibility_check;
+ALTER TABLE users ADD CONSTRAINT users_profile_visibility_check
+    CHECK (profile_visibility IN ('public', 'followers', 'private'));
@@ -0,0 +1,7 @@
+-- Подписка не требует согласия владельца, поэтому видимость только для
+-- подписчиков ничего не защищала. Такие профили становятся закрытыми.
+UPDATE users SET profile_visibility = 'private' WHERE profile_visibility = 'followers';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_profile_visibility_check;
+ALTER TABLE users ADD CONSTRAINT users_profile_visibility_check
+    CHECK (profile_visibility IN ('public', 'private'));
@@ -0,0 +1,9 @@
+// Пакет migrations встраивает SQL-миграции схемы в бинарные файлы.
+package migrations
+
+import "embed"
+
+// FS содержит файлы миграций вида NNNNNN_name.up.sql и NNNNNN_name.down.sql
+//
+//go:embed *.sql
+var FS embed.FS